@@ -5,8 +5,7 @@ import (
 	"crypto/md5"
 	"encoding/binary"
 	"encoding/json"
-	"log"
-	"math"
+	"fmt"
 	"os"
 	"sort"
 	"strings"
@@ -19,6 +18,13 @@ type Transaction struct {
 	date  time.Time
 	event *Event
 	hash  uint64
+
+	// envelope transactions are the unspent allowance of a budget envelope rather than an event
+	envelope bool
+
+	// paused transactions are occurrences skipped while their event is paused, they are shown but
+	// don't change the balance
+	paused bool
 }
 
 func (t *Transaction) repeats() bool {
@@ -26,21 +32,14 @@ func (t *Transaction) repeats() bool {
 }
 
 func (t *Transaction) isFirstOccurrence() bool {
-	return t.date.Equal(t.event.Date)
+	return t.date.Equal(t.event.firstOccurrence())
 }
 
+// calculateHash identifies the transaction's event by its ID, which unlike its description and
+// amount stays the same when it's edited and differs between an event and its duplicate
 func (t *Transaction) calculateHash() {
 	hasher := md5.New()
-
-	amount := make([]byte, 4)
-	binary.LittleEndian.PutUint32(amount, math.Float32bits(t.event.Amount))
-
-	frequency := make([]byte, 1)
-	frequency[0] = uint8(t.event.Frequency)
-
-	hasher.Write([]byte(t.event.Description))
-	hasher.Write(amount)
-	hasher.Write(frequency)
+	hasher.Write([]byte(t.event.ID))
 
 	// calculate the hash and store it in the transaction
 	hash := hasher.Sum(nil)
@@ -63,60 +62,152 @@ func (t byDate) Less(i int, j int) bool {
 	return t[i].date.Before(t[j].date)
 }
 
+// HistoryEntry records a transaction that has been marked done
+type HistoryEntry struct {
+	Date          time.Time
+	Description   string
+	Amount        float32
+	Category      string `json:",omitempty"`
+	LedgerAccount string `json:",omitempty"`
+}
+
 type Account struct {
 	config_path string
 	currency    accounting.Accounting
 
+	// set when this account is the sandbox of one of parent's scenarios
+	parent   *Account
+	scenario int
+
 	Balance float32
 	Events  []Event
+	History []HistoryEntry `json:",omitempty"`
+
+	Envelopes []Envelope      `json:",omitempty"`
+	Goals     []Goal          `json:",omitempty"`
+	Scenarios []Scenario      `json:",omitempty"`
+	Templates []EventTemplate `json:",omitempty"`
+
+	// lowest the balance should ever go, savings goals are only affordable if it stays above this.
+	// It's saved under its original name so existing files keep their floor.
+	BalanceFloor float32 `json:"MinimumBalance,omitempty"`
+
+	BankProfiles map[string]BankProfile `json:",omitempty"`
+
+	// account that holds the balance when exporting to plain text accounting
+	LedgerAccount string `json:",omitempty"`
+	Commodity     string `json:",omitempty"`
 }
 
-func newAccount(path *string) Account {
+func newAccount(path *string) (Account, error) {
+	var account Account
+
 	account_str, err := os.ReadFile(*path)
 	if err != nil {
-		log.Fatal(err)
+		return account, err
 	}
 
-	var account Account
 	if err := json.Unmarshal(account_str, &account); err != nil {
-		log.Fatal(err)
+		return account, fmt.Errorf("%s: %w", *path, err)
+	}
+
+	assignEventIDs(account.Events)
+	account.skipPaused(today())
+	for i := range account.Scenarios {
+		assignEventIDs(account.Scenarios[i].Events)
 	}
 
 	account.config_path = *path
 	account.currency = accounting.Accounting{Symbol: "$", Precision: 2}
-	return account
+	return account, nil
 }
 
 func (a *Account) addEvent(event *Event) {
+	event.ID = newEventID()
 	a.Events = append(a.Events, *event)
 }
 
+func (a *Account) ledgerAccount() string {
+	if a.LedgerAccount != "" {
+		return a.LedgerAccount
+	}
+
+	return "assets:checking"
+}
+
+func (a *Account) commodity() string {
+	if a.Commodity != "" {
+		return a.Commodity
+	}
+
+	return "USD"
+}
+
 func (a *Account) deleteEvent(i int) {
 	last := len(a.Events) - 1
 	a.Events[i] = a.Events[last]
 	a.Events = a.Events[:last]
 }
 
-func (a *Account) save() {
+// duplicateEvent appends a copy of event i which can then be changed independently
+func (a *Account) duplicateEvent(i int) {
+	duplicate := copyEvents(a.Events[i : i+1])[0]
+	duplicate.ID = newEventID()
+	a.Events = append(a.Events, duplicate)
+}
+
+// splitSeries ends the repeating event of tx the day before it and adds event to carry on in its
+// place
+func (a *Account) splitSeries(tx *Transaction, event *Event) {
+	until := tx.date.AddDate(0, 0, -1)
+	tx.event.Until = &until
+	if tx.event.ended() {
+		a.deleteEvent(a.findEventIndex(tx))
+	}
+
+	a.addEvent(event)
+}
+
+func (a *Account) save() error {
+	if a.isSandbox() {
+		a.sync()
+		return a.parent.save()
+	}
+
 	result, err := json.MarshalIndent(a, "", strings.Repeat(" ", 4))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	if err := os.WriteFile(a.config_path, result, 0); err != nil {
-		log.Fatal(err)
-	}
+	return os.WriteFile(a.config_path, result, 0)
 }
 
-func (a *Account) reload() {
-	account_str, err := os.ReadFile(a.config_path)
-	if err != nil {
-		log.Fatal(err)
+// reload replaces the account with what was last saved. The account is left untouched if the file
+// can't be read.
+func (a *Account) reload() error {
+	if a.isSandbox() {
+		if err := a.parent.reload(); err != nil {
+			return err
+		}
+
+		if a.scenario < len(a.parent.Scenarios) {
+			*a = *a.parent.sandbox(a.scenario)
+		}
+		return nil
 	}
 
-	if err := json.Unmarshal(account_str, a); err != nil {
-		log.Fatal(err)
+	account, err := newAccount(&a.config_path)
+	if err != nil {
+		return err
 	}
+
+	*a = account
+	return nil
+}
+
+// forecastHorizon returns the date up to which transactions are projected
+func forecastHorizon() time.Time {
+	return time.Now().AddDate(0, 4, 0)
 }
 
 func (a *Account) predict(until time.Time) []Transaction {
@@ -126,13 +217,65 @@ func (a *Account) predict(until time.Time) []Transaction {
 		transactions = append(transactions, a.Events[i].predict(until)...)
 	}
 
+	for i := range a.Envelopes {
+		transactions = append(transactions, a.predictEnvelope(&a.Envelopes[i], until)...)
+	}
+
 	sort.Sort(byDate(transactions))
 	return transactions
 }
 
+// pausedTransactions returns the occurrences skipped while their events are paused, in date order
+func (a *Account) pausedTransactions(until time.Time) []Transaction {
+	transactions := []Transaction{}
+
+	for i := range a.Events {
+		transactions = append(transactions, a.Events[i].occurrences(until, true)...)
+	}
+
+	sort.Sort(byDate(transactions))
+	return transactions
+}
+
+// skipPaused moves paused events past the occurrences they skipped that are now in the past, and
+// resumes those whose pause is over. An event that happens once is resumed on its own date, which
+// then shows as overdue rather than disappearing.
+func (a *Account) skipPaused(today time.Time) {
+	for i := len(a.Events) - 1; i >= 0; i-- {
+		event := &a.Events[i]
+		if !event.Paused {
+			continue
+		}
+
+		for event.Frequency != Once && event.pausedOn(event.Date) && event.Date.Before(today) {
+			event.Date = event.nextOccurrence(event.Date)
+		}
+
+		if event.ResumeOn != nil && !event.ResumeOn.After(today) {
+			event.Paused = false
+			event.ResumeOn = nil
+		}
+
+		if event.ended() {
+			a.deleteEvent(i)
+		}
+	}
+}
+
+// pauseEvent stops event i from occurring until the given date, or until it's resumed when nil
+func (a *Account) pauseEvent(i int, resume *time.Time) {
+	a.Events[i].Paused = true
+	a.Events[i].ResumeOn = resume
+}
+
+func (a *Account) resumeEvent(i int) {
+	a.Events[i].Paused = false
+	a.Events[i].ResumeOn = nil
+}
+
 func (a *Account) findEventIndex(tx *Transaction) int {
-	for i, event := range a.Events {
-		if *tx.event == event {
+	for i := range a.Events {
+		if tx.event == &a.Events[i] {
 			return i
 		}
 	}
@@ -140,7 +283,38 @@ func (a *Account) findEventIndex(tx *Transaction) int {
 	return -1
 }
 
+// findTransaction returns the occurrence on the given date of the event with the given hash, or nil
+// if there is none. Changing the account may move events around in memory, so transactions must be
+// found again this way after every change.
+func (a *Account) findTransaction(hash uint64, date time.Time) *Transaction {
+	for _, tx := range a.predict(date.AddDate(0, 0, 1)) {
+		if tx.hash == hash && tx.date.Equal(date) {
+			return &tx
+		}
+	}
+
+	return nil
+}
+
+func (a *Account) findEnvelope(tx *Transaction) *Envelope {
+	for i := range a.Envelopes {
+		if a.Envelopes[i].Category == tx.event.Category {
+			return &a.Envelopes[i]
+		}
+	}
+
+	return nil
+}
+
 func (a *Account) txComplete(tx *Transaction, update_balance bool) {
+	if tx.envelope {
+		// marking an envelope done means its remaining allowance was spent, there is nothing to skip
+		if envelope := a.findEnvelope(tx); envelope != nil && update_balance {
+			a.logSpending(envelope, -tx.event.Amount, "")
+		}
+		return
+	}
+
 	if tx.repeats() && !tx.isFirstOccurrence() {
 		// disallow marking done a future transaction generated by a repeating event
 		return
@@ -148,6 +322,19 @@ func (a *Account) txComplete(tx *Transaction, update_balance bool) {
 
 	if update_balance {
 		a.Balance += tx.event.Amount
+		a.History = append(a.History, HistoryEntry{
+			Date:          tx.date,
+			Description:   tx.event.Description,
+			Amount:        tx.event.Amount,
+			Category:      tx.event.Category,
+			LedgerAccount: tx.event.LedgerAccount,
+		})
+
+		if tx.event.Goal != "" {
+			if goal := a.findGoal(tx.event.Goal); goal != nil {
+				goal.Saved -= tx.event.Amount
+			}
+		}
 	}
 
 	if !tx.repeats() {
@@ -156,18 +343,36 @@ func (a *Account) txComplete(tx *Transaction, update_balance bool) {
 		return
 	}
 
-	tx.event.Date = tx.event.nextOccurrence(tx.event.Date)
+	tx.event.Date = tx.event.nextOccurrence(tx.date)
+	tx.event.resumeIfDue()
+
+	// a series is deleted once its last occurrence is done, the same as an event that happens once
+	if tx.event.ended() {
+		a.deleteEvent(a.findEventIndex(tx))
+	}
 }
 
 func (a *Account) txDatePrevious(tx *Transaction) {
+	if tx.envelope {
+		return
+	}
+
 	tx.event.Date = tx.event.Date.AddDate(0, 0, -1)
 }
 
 func (a *Account) txDateNext(tx *Transaction) {
+	if tx.envelope {
+		return
+	}
+
 	tx.event.Date = tx.event.Date.AddDate(0, 0, 1)
 }
 
 func (a *Account) txSetToToday(tx *Transaction) {
+	if tx.envelope {
+		return
+	}
+
 	if tx.repeats() && !tx.isFirstOccurrence() {
 		// doesn't make sense to place a future occurrence of a repeating event to be paid today when
 		// there is an earlier occurrence
@@ -182,9 +387,14 @@ func (a *Account) txSetToToday(tx *Transaction) {
 	}
 
 	new_event := *tx.event
+	new_event.ID = newEventID()
 	new_event.Frequency = Once
 	new_event.Date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
 	a.Events = append(a.Events, new_event)
 
-	tx.event.Date = tx.event.nextOccurrence(tx.event.Date)
+	tx.event.Date = tx.event.nextOccurrence(tx.date)
+	tx.event.resumeIfDue()
+	if tx.event.ended() {
+		a.deleteEvent(a.findEventIndex(tx))
+	}
 }
@@ -0,0 +1,553 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type CalendarViewKeyMap struct {
+	DayPrevious   key.Binding `mode:"month"`
+	DayNext       key.Binding `mode:"month"`
+	WeekPrevious  key.Binding `mode:"month"`
+	WeekNext      key.Binding `mode:"month"`
+	MonthPrevious key.Binding `mode:"month"`
+	MonthNext     key.Binding `mode:"month"`
+	Today         key.Binding `mode:"month"`
+
+	DatePrevious key.Binding `mode:"day"`
+	DateNext     key.Binding `mode:"day"`
+	Delete       key.Binding `mode:"day"`
+	Done         key.Binding `mode:"day"`
+	SetToday     key.Binding `mode:"day"`
+
+	Help    key.Binding
+	Confirm key.Binding `mode:"month"`
+	Cancel  key.Binding
+}
+
+func NewCalendarViewKeyMap() CalendarViewKeyMap {
+	k := CalendarViewKeyMap{
+		DayPrevious: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous day"),
+		),
+		DayNext: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next day"),
+		),
+		WeekPrevious: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "previous week"),
+		),
+		WeekNext: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "next week"),
+		),
+		MonthPrevious: key.NewBinding(
+			key.WithKeys("pgup", "["),
+			key.WithHelp("[/pgup", "previous month"),
+		),
+		MonthNext: key.NewBinding(
+			key.WithKeys("pgdown", "]"),
+			key.WithHelp("]/pgdown", "next month"),
+		),
+		Today: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "go to today"),
+		),
+
+		DatePrevious: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "date to previous day"),
+		),
+		DateNext: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "date to next day"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "delete"),
+		),
+		Done: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "done"),
+		),
+		SetToday: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "set today"),
+		),
+
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "show transactions"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+
+	settings.bind("calendar", &k)
+	return k
+}
+
+func (k CalendarViewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.MonthPrevious, k.MonthNext, k.Confirm, k.Cancel}
+}
+
+func (k CalendarViewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.DayPrevious, k.DayNext, k.WeekPrevious, k.WeekNext},
+		{k.MonthPrevious, k.MonthNext, k.Today},
+		{k.DatePrevious, k.DateNext, k.SetToday, k.Done, k.Delete},
+		{k.Confirm, k.Cancel},
+	}
+}
+
+// DayFlow holds the money in and out on a single day and the balance once the day is over
+type DayFlow struct {
+	net     float32
+	balance float32
+}
+
+const (
+	calendarCellWidth = 16
+	calendarDays      = 6 * 7
+)
+
+// CalendarView shows a month at a time with each day's net flow and end of day balance. Selecting a
+// day lists its transactions, which can be acted on just as in the forecast table.
+type CalendarView struct {
+	keymap CalendarViewKeyMap
+	help   help.Model
+
+	table table.Model
+
+	account      *Account
+	undo         *UndoHistory
+	confirmation Confirmation
+	day          time.Time
+	flows        map[time.Time]DayFlow
+	transactions []Transaction
+
+	SelectedStyle lipgloss.Style
+	TodayStyle    lipgloss.Style
+	OutsideStyle  lipgloss.Style
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func NewCalendarView(account *Account, undo *UndoHistory) CalendarView {
+	columns := []table.Column{
+		{Title: "Description", Width: 40},
+		{Title: "Income", Width: 15},
+		{Title: "Expense", Width: 15},
+	}
+
+	style := table.DefaultStyles()
+	style.Header = style.Header.
+		Bold(false).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true)
+
+	style.Selected = style.Selected.
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground).
+		Bold(false)
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithStyles(style),
+	)
+
+	t.KeyMap.PageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.PageDown = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageDown = key.NewBinding(key.WithDisabled())
+
+	return CalendarView{
+		keymap: NewCalendarViewKeyMap(),
+		help:   help.New(),
+
+		table: t,
+
+		account:      account,
+		undo:         undo,
+		confirmation: NewConfirmation(),
+		day:          today(),
+
+		SelectedStyle: lipgloss.NewStyle().Foreground(theme.SelectedForeground).Background(theme.SelectedBackground),
+		TodayStyle:    lipgloss.NewStyle().Bold(true).Underline(true),
+		OutsideStyle:  lipgloss.NewStyle().Foreground(theme.Muted),
+	}
+}
+
+// setAccount switches the calendar to another account, either the real one or a scenario's sandbox
+func (c *CalendarView) setAccount(account *Account) {
+	c.account = account
+}
+
+// browsing returns whether the view is moving around the month rather than acting on a day's
+// transactions
+func (c *CalendarView) browsing() bool {
+	return !c.table.Focused() && !c.confirmation.active()
+}
+
+func (c *CalendarView) browse() {
+	c.table.Blur()
+	c.regenerateRows()
+}
+
+// calendarStart returns the sunday on or before the first of the given day's month
+func calendarStart(day time.Time) time.Time {
+	first := startOfMonth(day)
+	return first.AddDate(0, 0, -int(first.Weekday()))
+}
+
+// dailyFlows returns the net flow and end of day balance for each day from start for the given
+// number of days. Overdue transactions count towards today's balance, so days in the past carry no
+// balance.
+func dailyFlows(balance float32, transactions []Transaction, start time.Time,
+	days int) map[time.Time]DayFlow {
+	now := today()
+	flows := map[time.Time]DayFlow{}
+
+	for _, transaction := range transactions {
+		day := startOfDay(transaction.date)
+		flow := flows[day]
+		flow.net += transaction.event.Amount
+		flows[day] = flow
+	}
+
+	day := start
+	if now.After(day) {
+		day = now
+	}
+
+	for end := start.AddDate(0, 0, days); day.Before(end); day = day.AddDate(0, 0, 1) {
+		for len(transactions) > 0 && !transactions[0].date.After(day) {
+			balance += transactions[0].event.Amount
+			transactions = transactions[1:]
+		}
+
+		flow := flows[day]
+		flow.balance = balance
+		flows[day] = flow
+	}
+
+	return flows
+}
+
+func (c *CalendarView) regenerateRows() {
+	// look far enough ahead to fill the month being shown, even past the forecast horizon
+	start := calendarStart(c.day)
+	until := forecastHorizon()
+	if end := start.AddDate(0, 0, calendarDays); end.After(until) {
+		until = end
+	}
+
+	transactions := c.account.predict(until)
+	c.flows = dailyFlows(c.account.Balance, transactions, start, calendarDays)
+
+	c.transactions = nil
+	for _, transaction := range transactions {
+		if sameDay(transaction.date, c.day) {
+			c.transactions = append(c.transactions, transaction)
+		}
+	}
+
+	rows := make([]table.Row, 0, len(c.transactions))
+	for _, transaction := range c.transactions {
+		var income string
+		var expense string
+
+		if transaction.event.Amount > 0 {
+			income = c.account.currency.FormatMoney(transaction.event.Amount)
+		} else {
+			expense = c.account.currency.FormatMoney(transaction.event.Amount * -1)
+		}
+
+		rows = append(rows, table.Row{transaction.event.Description, income, expense})
+	}
+
+	c.table.SetHeight(len(rows))
+	c.table.SetRows(rows)
+
+	// the cursor is left at -1 after moving through a day without transactions
+	c.table.SetCursor(c.table.Cursor())
+
+	if len(c.transactions) == 0 {
+		c.table.Blur()
+	}
+}
+
+func sameDay(a time.Time, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// cell renders the lines of a single day in the grid
+func (c *CalendarView) cell(day time.Time) []string {
+	lines := []string{fmt.Sprintf("%d", day.Day()), "", ""}
+
+	flow := c.flows[day]
+	if flow.net != 0 {
+		net := c.account.currency.FormatMoney(flow.net)
+		if flow.net > 0 {
+			net = "+" + net
+		}
+		lines[1] = net
+	}
+
+	// the balance is only known from today onwards
+	if !day.Before(today()) {
+		lines[2] = c.account.currency.FormatMoney(flow.balance)
+	}
+
+	style := lipgloss.NewStyle()
+	switch {
+	case sameDay(day, c.day):
+		style = c.SelectedStyle
+	case day.Month() != c.day.Month():
+		style = c.OutsideStyle
+	}
+
+	for i, line := range lines {
+		line = fmt.Sprintf(" %-*s", calendarCellWidth-1, line)
+		if i == 0 && sameDay(day, today()) {
+			line = " " + c.TodayStyle.Render(fmt.Sprintf("%d", day.Day())) +
+				strings.Repeat(" ", calendarCellWidth-1-len(fmt.Sprintf("%d", day.Day())))
+		}
+
+		// negative balances stand out unless the cell is selected
+		if i == 2 && flow.balance < 0 && !day.Before(today()) && !sameDay(day, c.day) {
+			line = theme.negative(line)
+		}
+
+		lines[i] = style.Render(line)
+	}
+
+	return lines
+}
+
+func (c *CalendarView) View() string {
+	var b strings.Builder
+	b.WriteString(c.day.Format("January 2006"))
+	b.WriteString("\n\n")
+
+	start := calendarStart(c.day)
+	for i := 0; i < 7; i++ {
+		b.WriteString(fmt.Sprintf(" %-*s", calendarCellWidth-1, start.AddDate(0, 0, i).Format("Monday")))
+	}
+	b.WriteString("\n")
+
+	separator := strings.Repeat(strings.Repeat("─", calendarCellWidth), 7)
+	for week := 0; week < calendarDays/7; week++ {
+		b.WriteString(separator)
+		b.WriteString("\n")
+
+		cells := make([][]string, 7)
+		for i := range cells {
+			cells[i] = c.cell(start.AddDate(0, 0, week*7+i))
+		}
+
+		for line := range cells[0] {
+			for i := range cells {
+				b.WriteString(cells[i][line])
+			}
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(separator)
+	b.WriteString("\n\n")
+
+	b.WriteString(c.day.Format("Monday, January 2, 2006"))
+	b.WriteString("\n")
+	if len(c.transactions) == 0 {
+		b.WriteString("No transactions")
+	} else {
+		b.WriteString(c.table.View())
+	}
+	b.WriteString("\n\n")
+	if c.confirmation.active() {
+		b.WriteString(c.confirmation.View())
+		b.WriteString("\n\n")
+	}
+	b.WriteString(c.help.View(c.keymap))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// moveDay selects the day n days away, following it into the next or previous month
+func (c *CalendarView) moveDay(n int) {
+	c.day = c.day.AddDate(0, 0, n)
+	c.table.SetCursor(0)
+}
+
+// moveMonth selects the same day n months away, or the last day of the month if it is shorter
+func (c *CalendarView) moveMonth(n int) {
+	first := startOfMonth(c.day).AddDate(0, n, 0)
+	last := first.AddDate(0, 1, -1)
+
+	day := c.day.Day()
+	if day > last.Day() {
+		day = last.Day()
+	}
+
+	c.day = time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.Local)
+	c.table.SetCursor(0)
+}
+
+func (c *CalendarView) setCursorToTransactionWithHash(hash uint64) {
+	index := 0
+	for i, transaction := range c.transactions {
+		if transaction.hash == hash {
+			index = i
+			break
+		}
+	}
+
+	c.table.SetCursor(index)
+}
+
+func (c *CalendarView) Update(msg tea.Msg) tea.Cmd {
+	if handled, cmd := c.confirmation.Update(msg); handled {
+		c.regenerateRows()
+		return cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.help.Width = msg.Width
+	case tea.MouseMsg:
+		// clicking one of the day's transactions picks it the way confirming the day does
+		if !c.confirmation.active() && handleTableMouse(&c.table, c.View, msg) {
+			c.table.Focus()
+		}
+		return nil
+	case tea.KeyMsg:
+		if key.Matches(msg, c.keymap.Help) {
+			c.help.ShowAll = !c.help.ShowAll
+			return nil
+		}
+
+		if c.table.Focused() {
+			return c.handleTransactionInput(msg)
+		}
+
+		switch {
+		case key.Matches(msg, c.keymap.DayPrevious):
+			c.moveDay(-1)
+		case key.Matches(msg, c.keymap.DayNext):
+			c.moveDay(1)
+		case key.Matches(msg, c.keymap.WeekPrevious):
+			c.moveDay(-7)
+		case key.Matches(msg, c.keymap.WeekNext):
+			c.moveDay(7)
+		case key.Matches(msg, c.keymap.MonthPrevious):
+			c.moveMonth(-1)
+		case key.Matches(msg, c.keymap.MonthNext):
+			c.moveMonth(1)
+		case key.Matches(msg, c.keymap.Today):
+			c.day = today()
+			c.table.SetCursor(0)
+		case key.Matches(msg, c.keymap.Confirm):
+			c.regenerateRows()
+			if len(c.transactions) > 0 {
+				c.table.Focus()
+			}
+			return nil
+		}
+
+		c.regenerateRows()
+	}
+
+	return nil
+}
+
+func (c *CalendarView) handleTransactionInput(msg tea.KeyMsg) tea.Cmd {
+	if key.Matches(msg, c.keymap.Cancel) {
+		c.table.Blur()
+		return nil
+	}
+
+	tx := c.transactions[c.table.Cursor()]
+
+	switch {
+	case key.Matches(msg, c.keymap.DatePrevious):
+		// the selected day follows the transaction so it stays in view
+		hash := tx.hash
+		c.undo.remember(c.account)
+		c.account.txDatePrevious(&tx)
+		if !tx.envelope {
+			c.moveDay(-1)
+		}
+		c.regenerateRows()
+		c.setCursorToTransactionWithHash(hash)
+		c.table.Focus()
+		return nil
+	case key.Matches(msg, c.keymap.DateNext):
+		hash := tx.hash
+		c.undo.remember(c.account)
+		c.account.txDateNext(&tx)
+		if !tx.envelope {
+			c.moveDay(1)
+		}
+		c.regenerateRows()
+		c.setCursorToTransactionWithHash(hash)
+		c.table.Focus()
+		return nil
+	case key.Matches(msg, c.keymap.Delete):
+		if tx.envelope {
+			return status("Envelopes can't be deleted")
+		}
+
+		if tx.repeats() && !tx.isFirstOccurrence() {
+			return status("Only the next %s can be deleted", tx.event.Description)
+		}
+
+		return c.confirmation.ask(fmt.Sprintf("Delete %s?", tx.event.Description), func() tea.Cmd {
+			c.undo.remember(c.account)
+			c.account.txComplete(&tx, false)
+			return status("Deleted %s", tx.event.Description)
+		})
+	case key.Matches(msg, c.keymap.Done):
+		if !tx.envelope && tx.repeats() && !tx.isFirstOccurrence() {
+			return status("Only the next %s can be marked done", tx.event.Description)
+		}
+
+		prompt := fmt.Sprintf("Mark %s done and change the balance by %s?", tx.event.Description,
+			c.account.currency.FormatMoney(tx.event.Amount))
+		return c.confirmation.ask(prompt, func() tea.Cmd {
+			c.undo.remember(c.account)
+			c.account.txComplete(&tx, true)
+			return status("Marked %s done", tx.event.Description)
+		})
+	case key.Matches(msg, c.keymap.SetToday):
+		c.undo.remember(c.account)
+		c.account.txSetToToday(&tx)
+		c.regenerateRows()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	c.table, cmd = c.table.Update(msg)
+	return cmd
+}
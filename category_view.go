@@ -0,0 +1,257 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type CategoryViewKeyMap struct {
+	Help   key.Binding
+	Cancel key.Binding
+
+	LineUp     key.Binding
+	LineDown   key.Binding
+	GotoTop    key.Binding
+	GotoBottom key.Binding
+}
+
+func NewCategoryViewKeyMap() CategoryViewKeyMap {
+	k := CategoryViewKeyMap{
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back to forecast"),
+		),
+
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+	}
+
+	settings.bind("category", &k)
+	return k
+}
+
+func (k CategoryViewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Cancel}
+}
+
+func (k CategoryViewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.LineUp, k.LineDown, k.GotoTop, k.GotoBottom},
+		{k.Cancel},
+	}
+}
+
+type CategoryTotal struct {
+	Month    time.Time
+	Category string
+	Income   float32
+	Expense  float32
+}
+
+// categoryTotals sums the income and expenses of the given transactions per category per month,
+// ordered by month and then by category
+func categoryTotals(transactions []Transaction) []CategoryTotal {
+	type group struct {
+		month    time.Time
+		category string
+	}
+
+	totals := map[group]*CategoryTotal{}
+	for _, transaction := range transactions {
+		g := group{
+			month:    time.Date(transaction.date.Year(), transaction.date.Month(), 1, 0, 0, 0, 0, time.Local),
+			category: transaction.event.category(),
+		}
+
+		total, ok := totals[g]
+		if !ok {
+			total = &CategoryTotal{Month: g.month, Category: g.category}
+			totals[g] = total
+		}
+
+		if transaction.event.Amount > 0 {
+			total.Income += transaction.event.Amount
+		} else {
+			total.Expense -= transaction.event.Amount
+		}
+	}
+
+	result := make([]CategoryTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+
+	sort.Slice(result, func(i int, j int) bool {
+		if result[i].Month.Equal(result[j].Month) {
+			return result[i].Category < result[j].Category
+		}
+
+		return result[i].Month.Before(result[j].Month)
+	})
+
+	return result
+}
+
+// categories returns the sorted, distinct categories of the account's events
+func (a *Account) categories() []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for i := range a.Events {
+		category := a.Events[i].category()
+		if !seen[category] {
+			seen[category] = true
+			result = append(result, category)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+type CategoryView struct {
+	keymap CategoryViewKeyMap
+	help   help.Model
+
+	table table.Model
+
+	account *Account
+}
+
+func NewCategoryView(account *Account) CategoryView {
+	columns := []table.Column{
+		{Title: "Month", Width: 20},
+		{Title: "Category", Width: 30},
+		{Title: "Income", Width: 15},
+		{Title: "Expense", Width: 15},
+		{Title: "Net", Width: 15},
+	}
+
+	style := table.DefaultStyles()
+	style.Header = style.Header.
+		Bold(false).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true)
+
+	style.Selected = style.Selected.
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground).
+		Bold(false)
+
+	t := table.New(
+		table.WithFocused(true),
+		table.WithColumns(columns),
+		table.WithStyles(style),
+	)
+
+	t.KeyMap.PageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.PageDown = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageDown = key.NewBinding(key.WithDisabled())
+
+	keymap := NewCategoryViewKeyMap()
+	t.KeyMap.LineUp = keymap.LineUp
+	t.KeyMap.LineDown = keymap.LineDown
+	t.KeyMap.GotoTop = keymap.GotoTop
+	t.KeyMap.GotoBottom = keymap.GotoBottom
+
+	return CategoryView{
+		keymap: keymap,
+		help:   help.New(),
+
+		table:   t,
+		account: account,
+	}
+}
+
+// setAccount switches the summary to another account, either the real one or a scenario's sandbox
+func (c *CategoryView) setAccount(account *Account) {
+	c.account = account
+}
+
+func (c *CategoryView) regenerateRows() {
+	totals := categoryTotals(c.account.predict(forecastHorizon()))
+
+	rows := make([]table.Row, 0, len(totals))
+	for i, total := range totals {
+		// only name each month once to make the grouping easier to see
+		month := total.Month.Format("January 2006")
+		if i > 0 && totals[i-1].Month.Equal(total.Month) {
+			month = ""
+		}
+
+		var income string
+		var expense string
+		if total.Income != 0 {
+			income = c.account.currency.FormatMoney(total.Income)
+		}
+		if total.Expense != 0 {
+			expense = c.account.currency.FormatMoney(total.Expense)
+		}
+
+		rows = append(rows, table.Row{
+			month,
+			total.Category,
+			income,
+			expense,
+			c.account.currency.FormatMoney(total.Income - total.Expense),
+		})
+	}
+
+	c.table.SetHeight(len(rows))
+	c.table.SetRows(rows)
+	c.table.SetCursor(c.table.Cursor())
+}
+
+func (c *CategoryView) View() string {
+	var b strings.Builder
+	b.WriteString("Projected totals per category")
+	b.WriteString("\n\n")
+	b.WriteString(c.table.View())
+	b.WriteString("\n\n")
+	b.WriteString(c.help.View(c.keymap))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (c *CategoryView) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.help.Width = msg.Width
+	case tea.MouseMsg:
+		handleTableMouse(&c.table, c.View, msg)
+		return nil
+	case tea.KeyMsg:
+		if key.Matches(msg, c.keymap.Help) {
+			c.help.ShowAll = !c.help.ShowAll
+		}
+	}
+
+	var cmd tea.Cmd
+	c.table, cmd = c.table.Update(msg)
+	return cmd
+}
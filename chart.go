@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/leekchan/accounting"
+)
+
+type ChartPoint struct {
+	date    time.Time
+	balance float32
+}
+
+// dailyBalances returns the balance at the end of every day from today until the given date. Overdue
+// transactions count towards today's balance.
+func dailyBalances(balance float32, transactions []Transaction, until time.Time) []ChartPoint {
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	points := []ChartPoint{}
+	for ; day.Before(until); day = day.AddDate(0, 0, 1) {
+		for len(transactions) > 0 && !transactions[0].date.After(day) {
+			balance += transactions[0].event.Amount
+			transactions = transactions[1:]
+		}
+
+		points = append(points, ChartPoint{date: day, balance: balance})
+	}
+
+	return points
+}
+
+// Chart plots the daily balance as a line, with a cursor that can be moved across it to read off
+// the balance on a particular day
+type Chart struct {
+	width  int
+	height int
+
+	points    []ChartPoint
+	threshold float32
+	cursor    int
+
+	LineStyle      lipgloss.Style
+	MinimumStyle   lipgloss.Style
+	ZeroStyle      lipgloss.Style
+	ThresholdStyle lipgloss.Style
+	CursorStyle    lipgloss.Style
+}
+
+func NewChart() Chart {
+	return Chart{
+		width:  100,
+		height: 20,
+
+		LineStyle:      lipgloss.NewStyle().Foreground(theme.Accent),
+		MinimumStyle:   lipgloss.NewStyle().Foreground(theme.Negative).Bold(true),
+		ZeroStyle:      lipgloss.NewStyle().Foreground(theme.Muted),
+		ThresholdStyle: lipgloss.NewStyle().Foreground(theme.Warning),
+		CursorStyle:    lipgloss.NewStyle().Foreground(theme.SelectedForeground).Background(theme.SelectedBackground),
+	}
+}
+
+func (c *Chart) setSize(width int, height int) {
+	c.width = width
+	c.height = height
+	c.clampCursor()
+}
+
+func (c *Chart) setPoints(points []ChartPoint, threshold float32) {
+	c.points = points
+	c.threshold = threshold
+	c.clampCursor()
+}
+
+const chartLabelWidth = 14
+
+func (c *Chart) plotWidth() int {
+	width := c.width - chartLabelWidth - 1
+	if width < 1 {
+		return 1
+	}
+
+	return width
+}
+
+func (c *Chart) plotHeight() int {
+	if c.height < 3 {
+		return 3
+	}
+
+	return c.height
+}
+
+func (c *Chart) clampCursor() {
+	if c.cursor >= c.plotWidth() {
+		c.cursor = c.plotWidth() - 1
+	}
+	if c.cursor < 0 {
+		c.cursor = 0
+	}
+}
+
+func (c *Chart) moveCursor(n int) {
+	c.cursor += n
+	c.clampCursor()
+}
+
+func (c *Chart) cursorStart() {
+	c.cursor = 0
+}
+
+func (c *Chart) cursorEnd() {
+	c.cursor = c.plotWidth() - 1
+}
+
+// column returns the lowest point among the days plotted in column x, so that a dip in the balance
+// is never hidden by squeezing several days into one column
+func (c *Chart) column(x int) (ChartPoint, bool) {
+	if len(c.points) == 0 {
+		return ChartPoint{}, false
+	}
+
+	width := c.plotWidth()
+	start := x * len(c.points) / width
+	end := (x + 1) * len(c.points) / width
+	if end <= start {
+		end = start + 1
+	}
+	if start >= len(c.points) {
+		return ChartPoint{}, false
+	}
+	if end > len(c.points) {
+		end = len(c.points)
+	}
+
+	lowest := c.points[start]
+	for _, point := range c.points[start:end] {
+		if point.balance < lowest.balance {
+			lowest = point
+		}
+	}
+
+	return lowest, true
+}
+
+func (c *Chart) View(currency accounting.Accounting) string {
+	if len(c.points) == 0 {
+		return "Nothing to plot"
+	}
+
+	width := c.plotWidth()
+	height := c.plotHeight()
+
+	// the vertical range always includes zero and the threshold so that both lines are visible
+	low := c.threshold
+	high := c.threshold
+	if low > 0 {
+		low = 0
+	}
+	if high < 0 {
+		high = 0
+	}
+
+	columns := make([]ChartPoint, width)
+	valid := make([]bool, width)
+	minimum := -1
+	for x := 0; x < width; x++ {
+		columns[x], valid[x] = c.column(x)
+		if !valid[x] {
+			continue
+		}
+
+		if columns[x].balance < low {
+			low = columns[x].balance
+		}
+		if columns[x].balance > high {
+			high = columns[x].balance
+		}
+		if minimum < 0 || columns[x].balance < columns[minimum].balance {
+			minimum = x
+		}
+	}
+
+	if high == low {
+		high = low + 1
+	}
+
+	row := func(value float32) int {
+		return int(float32(height-1) * (high - value) / (high - low))
+	}
+
+	zero := row(0)
+	threshold := row(c.threshold)
+
+	var b strings.Builder
+	for y := 0; y < height; y++ {
+		var label string
+		switch y {
+		case 0:
+			label = currency.FormatMoney(high)
+		case height - 1:
+			label = currency.FormatMoney(low)
+		case zero:
+			label = currency.FormatMoney(0)
+		case threshold:
+			label = currency.FormatMoney(c.threshold)
+		}
+		b.WriteString(fmt.Sprintf("%*s ", chartLabelWidth, label))
+
+		for x := 0; x < width; x++ {
+			char := " "
+			style := lipgloss.NewStyle()
+
+			switch y {
+			case zero:
+				char = "─"
+				style = c.ZeroStyle
+			case threshold:
+				char = "┄"
+				style = c.ThresholdStyle
+			}
+
+			if valid[x] {
+				value := row(columns[x].balance)
+
+				// join consecutive points with a vertical line so the chart reads as a line
+				previous := value
+				if x > 0 && valid[x-1] {
+					previous = row(columns[x-1].balance)
+				}
+
+				top := value
+				bottom := previous
+				if top > bottom {
+					top, bottom = bottom, top
+				}
+
+				switch {
+				case y == value:
+					char = "•"
+					style = c.LineStyle
+					if x == minimum {
+						char = "▼"
+						style = c.MinimumStyle
+					}
+				case y > top && y < bottom:
+					char = "│"
+					style = c.LineStyle
+				}
+			}
+
+			if x == c.cursor {
+				style = c.CursorStyle
+			}
+
+			b.WriteString(style.Render(char))
+		}
+
+		b.WriteString("\n")
+	}
+
+	if point, ok := c.column(c.cursor); ok {
+		b.WriteString(fmt.Sprintf("%*s %s: %s", chartLabelWidth, "", point.date.Format("January 2, 2006"),
+			currency.FormatMoney(point.balance)))
+	}
+	if minimum >= 0 {
+		b.WriteString(fmt.Sprintf("    lowest %s on %s", currency.FormatMoney(columns[minimum].balance),
+			columns[minimum].date.Format("January 2, 2006")))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// openOutput returns stdout if path is empty, otherwise creates the file at path
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+
+	return os.Create(path)
+}
+
+func runSummary(account *Account, args []string) {
+	flags := flag.NewFlagSet("summary", flag.ExitOnError)
+	flags.Parse(args)
+
+	transactions := account.predict(forecastHorizon())
+	summary := account.summarize(transactions)
+
+	fmt.Fprintf(os.Stdout, "Current balance: %s\n", account.currency.FormatMoney(account.Balance))
+	fmt.Fprintf(os.Stdout, "Minimum balance: %s on %s\n", account.currency.FormatMoney(summary.MinimumBalance),
+		summary.MinimumDate.Format("January 2, 2006"))
+	fmt.Fprintf(os.Stdout, "Safe to spend:   %s\n", account.currency.FormatMoney(summary.SafeToSpend))
+}
+
+func runLedgerExport(account *Account, command string, args []string) {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	output := flags.String("o", "", "output file (default stdout)")
+	history := flags.Bool("history", true, "include completed transactions")
+	flags.Parse(args)
+
+	w, err := openOutput(*output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer w.Close()
+
+	entries := ledgerEntries(account, account.predict(forecastHorizon()), *history)
+
+	switch command {
+	case "hledger":
+		err = writeHledger(w, account, entries)
+	case "beancount":
+		err = writeBeancount(w, account, entries)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runICS(account *Account, args []string) {
+	flags := flag.NewFlagSet("ics", flag.ExitOnError)
+	output := flags.String("o", "", "output file (default stdout)")
+	occurrences := flags.Bool("occurrences", false,
+		"write each projected occurrence with its running balance instead of recurring events")
+	flags.Parse(args)
+
+	w, err := openOutput(*output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := writeICS(w, account, account.predict(forecastHorizon()), *occurrences); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runImport(account *Account, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: import [flags] file.{csv,ofx,qfx}\n\n")
+		fmt.Fprintf(flags.Output(), "OFX and QFX statements don't need a bank profile and set the balance from the\n")
+		fmt.Fprintf(flags.Output(), "statement's ledger balance.\n\n")
+		flags.PrintDefaults()
+	}
+
+	name := flags.String("profile", "default", "bank profile used to read the CSV file")
+	save := flags.Bool("save", false, "save the column mapping given on the command line to the bank profile")
+	delimiter := flags.String("delimiter", "", "field delimiter")
+	date_column := flags.String("date-column", "", "name of the date column")
+	date_format := flags.String("date-format", "", "format of the date column, e.g. 01/02/2006 or 2006-01-02")
+	description_column := flags.String("description-column", "", "name of the description column")
+	amount_column := flags.String("amount-column", "", "name of the signed amount column")
+	debit_column := flags.String("debit-column", "", "name of the debit column")
+	credit_column := flags.String("credit-column", "", "name of the credit column")
+	invert := flags.Bool("invert", false, "treat positive amounts as money spent")
+	tolerance := flags.Float64("amount-tolerance", defaultReconcileOptions().AmountTolerance,
+		"relative amount difference allowed when matching, e.g. 0.1 for 10%")
+	window := flags.Int("date-window", defaultReconcileOptions().DateWindow,
+		"number of days a bank transaction may be from its forecasted date")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	profile, ok := account.BankProfiles[*name]
+	if !ok {
+		profile = defaultBankProfile()
+	}
+
+	// command line flags override whatever is stored in the profile
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "delimiter":
+			profile.Delimiter = *delimiter
+		case "date-column":
+			profile.DateColumn = *date_column
+		case "date-format":
+			profile.DateFormat = *date_format
+		case "description-column":
+			profile.DescriptionColumn = *description_column
+		case "amount-column":
+			profile.AmountColumn = *amount_column
+		case "debit-column":
+			profile.DebitColumn = *debit_column
+			profile.AmountColumn = ""
+		case "credit-column":
+			profile.CreditColumn = *credit_column
+			profile.AmountColumn = ""
+		case "invert":
+			profile.InvertAmounts = *invert
+		}
+	})
+
+	options := ReconcileOptions{
+		AmountTolerance: *tolerance,
+		DateWindow:      *window,
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(flags.Arg(0))) {
+	case ".ofx", ".qfx":
+		statement, err := parseOFX(file)
+		if err != nil {
+			log.Fatalf("Error reading %s: %v", flags.Arg(0), err)
+		}
+
+		var balance *float32
+		if statement.HasBalance {
+			balance = &statement.Balance
+		}
+
+		reconcileInteractively(account, statement.Transactions, balance, options)
+		return
+	}
+
+	bank, err := parseCSV(file, profile)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", flags.Arg(0), err)
+	}
+
+	if *save {
+		if account.BankProfiles == nil {
+			account.BankProfiles = map[string]BankProfile{}
+		}
+
+		account.BankProfiles[*name] = profile
+		if err := account.save(); err != nil {
+			log.Fatalf("Error saving profile: %v", err)
+		}
+	}
+
+	reconcileInteractively(account, bank, nil, options)
+}
+
+// reconcileInteractively matches bank transactions against the forecast and opens the
+// reconciliation screen so the matches can be reviewed before they are applied. If balance is given,
+// the account balance is set to it once the matches are applied.
+func reconcileInteractively(account *Account, bank []BankTransaction, balance *float32,
+	options ReconcileOptions) {
+	if len(bank) == 0 && balance == nil {
+		fmt.Fprintln(os.Stdout, "No transactions to import")
+		return
+	}
+
+	// bank transactions may be dated a little after the forecasted transactions they match
+	latest := time.Now()
+	for _, b := range bank {
+		if b.Date.After(latest) {
+			latest = b.Date
+		}
+	}
+
+	transactions := account.predict(latest.AddDate(0, 0, options.DateWindow+1))
+	matches := reconcile(bank, transactions, options)
+	account.markImported(matches)
+
+	tui := newTui(account)
+	tui.reconcile(matches, balance)
+	tui.run()
+}
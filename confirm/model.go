@@ -0,0 +1,126 @@
+package confirm
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type KeyMap struct {
+	Yes     key.Binding
+	No      key.Binding
+	Toggle  key.Binding
+	Confirm key.Binding
+}
+
+func NewKeyMap() KeyMap {
+	return KeyMap{
+		Yes:     key.NewBinding(key.WithKeys("y")),
+		No:      key.NewBinding(key.WithKeys("n", "esc")),
+		Toggle:  key.NewBinding(key.WithKeys("left", "right", "h", "l", "tab")),
+		Confirm: key.NewBinding(key.WithKeys("enter")),
+	}
+}
+
+// ResultMsg is sent once the question has been answered
+type ResultMsg struct {
+	Confirmed bool
+}
+
+// Model asks a yes or no question. It takes all keypresses while focused and sends a ResultMsg once
+// answered, after which it blurs itself.
+type Model struct {
+	Prompt string
+	KeyMap KeyMap
+
+	yes   bool
+	focus bool
+
+	PromptStyle   lipgloss.Style
+	SelectedStyle lipgloss.Style
+	TextStyle     lipgloss.Style
+}
+
+func New() Model {
+	return Model{
+		KeyMap: NewKeyMap(),
+
+		yes:   false,
+		focus: false,
+
+		PromptStyle:   lipgloss.NewStyle().Bold(true),
+		SelectedStyle: lipgloss.NewStyle().Reverse(true),
+		TextStyle:     lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+	}
+}
+
+// Ask focuses the model with the given question. No is highlighted to begin with so that pressing
+// enter by accident is harmless.
+func (m *Model) Ask(prompt string) {
+	m.Prompt = prompt
+	m.yes = false
+	m.focus = true
+}
+
+func (m *Model) Focus() {
+	m.focus = true
+}
+
+func (m *Model) Blur() {
+	m.focus = false
+}
+
+func (m Model) Focused() bool {
+	return m.focus
+}
+
+func (m *Model) answer(yes bool) tea.Cmd {
+	m.focus = false
+	return func() tea.Msg {
+		return ResultMsg{Confirmed: yes}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if !m.focus {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.Yes):
+			return m, m.answer(true)
+		case key.Matches(msg, m.KeyMap.No):
+			return m, m.answer(false)
+		case key.Matches(msg, m.KeyMap.Confirm):
+			return m, m.answer(m.yes)
+		case key.Matches(msg, m.KeyMap.Toggle):
+			m.yes = !m.yes
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) View() string {
+	if !m.focus {
+		return ""
+	}
+
+	yes := m.TextStyle
+	no := m.SelectedStyle
+	if m.yes {
+		yes, no = no, yes
+	}
+
+	var b strings.Builder
+	b.WriteString(m.PromptStyle.Render(m.Prompt))
+	b.WriteString("  ")
+	b.WriteString(yes.Render(" Yes "))
+	b.WriteString(" ")
+	b.WriteString(no.Render(" No "))
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fsareshwala/forecash/confirm"
+)
+
+// Confirmation holds back a destructive action until the user confirms it, unless confirmations
+// have been turned off in the settings
+type Confirmation struct {
+	model   confirm.Model
+	pending func() tea.Cmd
+}
+
+func NewConfirmation() Confirmation {
+	model := confirm.New()
+	model.SelectedStyle = lipgloss.NewStyle().
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground)
+	model.TextStyle = lipgloss.NewStyle().Foreground(theme.Muted)
+	settings.bind("confirm", &model.KeyMap)
+
+	return Confirmation{model: model}
+}
+
+// ask runs the action once the question is answered yes
+func (c *Confirmation) ask(prompt string, action func() tea.Cmd) tea.Cmd {
+	if settings.SkipConfirmation {
+		return action()
+	}
+
+	c.pending = action
+	c.model.Ask(prompt)
+	return nil
+}
+
+// active returns whether a question is waiting for an answer
+func (c *Confirmation) active() bool {
+	return c.model.Focused()
+}
+
+// Update returns whether the message was meant for the confirmation, along with the command to run
+func (c *Confirmation) Update(msg tea.Msg) (bool, tea.Cmd) {
+	if result, ok := msg.(confirm.ResultMsg); ok {
+		action := c.pending
+		c.pending = nil
+
+		if result.Confirmed && action != nil {
+			return true, action()
+		}
+		return true, nil
+	}
+
+	// only keypresses are taken while asking, everything else goes on as usual
+	if _, ok := msg.(tea.KeyMsg); !ok || !c.model.Focused() {
+		return false, nil
+	}
+
+	var cmd tea.Cmd
+	c.model, cmd = c.model.Update(msg)
+	return true, cmd
+}
+
+func (c *Confirmation) View() string {
+	return c.model.View()
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BankProfile describes how to read the CSV export of a particular bank. Columns are identified by
+// the names in the CSV header row.
+type BankProfile struct {
+	Delimiter         string `json:",omitempty"`
+	DateColumn        string
+	DateFormat        string
+	DescriptionColumn string
+
+	// either a single signed amount column or separate debit and credit columns
+	AmountColumn string `json:",omitempty"`
+	DebitColumn  string `json:",omitempty"`
+	CreditColumn string `json:",omitempty"`
+
+	// some banks, credit cards in particular, report money spent as a positive number
+	InvertAmounts bool `json:",omitempty"`
+}
+
+func defaultBankProfile() BankProfile {
+	return BankProfile{
+		Delimiter:         ",",
+		DateColumn:        "Date",
+		DateFormat:        "01/02/2006",
+		DescriptionColumn: "Description",
+		AmountColumn:      "Amount",
+	}
+}
+
+func (p *BankProfile) validate() error {
+	if p.DateColumn == "" || p.DescriptionColumn == "" {
+		return fmt.Errorf("bank profile must set the date and description columns")
+	}
+
+	if p.AmountColumn == "" && p.DebitColumn == "" && p.CreditColumn == "" {
+		return fmt.Errorf("bank profile must set either an amount column or debit and credit columns")
+	}
+
+	if len([]rune(p.Delimiter)) > 1 {
+		return fmt.Errorf("bank profile delimiter must be a single character: %q", p.Delimiter)
+	}
+
+	return nil
+}
+
+// parseMoney parses amounts as banks tend to write them: "1,234.56", "$-12.00", "(12.00)"
+func parseMoney(str string) (float32, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return 0, nil
+	}
+
+	negative := false
+	if strings.HasPrefix(str, "(") && strings.HasSuffix(str, ")") {
+		negative = true
+		str = str[1 : len(str)-1]
+	}
+
+	str = strings.NewReplacer("$", "", ",", "", " ", "").Replace(str)
+	result, err := strconv.ParseFloat(str, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount: %q", str)
+	}
+
+	if negative {
+		result = -result
+	}
+
+	return float32(result), nil
+}
+
+func parseCSV(r io.Reader, profile BankProfile) ([]BankTransaction, error) {
+	if err := profile.validate(); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if profile.Delimiter != "" {
+		reader.Comma = []rune(profile.Delimiter)[0]
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %v", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// the first column may be prefixed with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	column := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+
+		i, ok := columns[name]
+		if !ok {
+			return -1, fmt.Errorf("column %q not found in CSV header", name)
+		}
+
+		return i, nil
+	}
+
+	date_column, err := column(profile.DateColumn)
+	if err != nil {
+		return nil, err
+	}
+	description_column, err := column(profile.DescriptionColumn)
+	if err != nil {
+		return nil, err
+	}
+	amount_column, err := column(profile.AmountColumn)
+	if err != nil {
+		return nil, err
+	}
+	debit_column, err := column(profile.DebitColumn)
+	if err != nil {
+		return nil, err
+	}
+	credit_column, err := column(profile.CreditColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	field := func(record []string, i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+
+		return record[i]
+	}
+
+	transactions := []BankTransaction{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		date, err := time.ParseInLocation(profile.DateFormat, strings.TrimSpace(field(record, date_column)),
+			time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		var amount float32
+		if amount_column >= 0 {
+			amount, err = parseMoney(field(record, amount_column))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		} else {
+			debit, err := parseMoney(field(record, debit_column))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+
+			credit, err := parseMoney(field(record, credit_column))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+
+			// debits are sometimes written as negative numbers and sometimes not
+			if debit > 0 {
+				debit = -debit
+			}
+			amount = credit + debit
+		}
+
+		if profile.InvertAmounts {
+			amount = -amount
+		}
+
+		transactions = append(transactions, BankTransaction{
+			Date:        date,
+			Description: strings.TrimSpace(field(record, description_column)),
+			Amount:      amount,
+		})
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateHelp = "today, tomorrow, fri, next fri, +2w, -3d, 2026-11-03 or 11/3"
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// parseDate parses a date relative to now. Besides today, tomorrow and yesterday it accepts:
+//
+//	fri, this fri   the coming Friday, which may be today
+//	next fri        the first Friday after today
+//	last fri        the last Friday before today
+//	+2w, -3d        days, weeks, months or years from today
+//	2026-11-03      a full date
+//	11/3, 11/3/26   a month and day, in this year unless one is given
+func parseDate(str string, now time.Time) (time.Time, error) {
+	today := startOfDay(now)
+	words := strings.Fields(strings.ToLower(str))
+
+	if len(words) == 0 {
+		return today, fmt.Errorf("enter a date such as %s", dateHelp)
+	}
+
+	switch strings.Join(words, " ") {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if len(words) <= 2 {
+		modifier := ""
+		if len(words) == 2 {
+			modifier = words[0]
+		}
+
+		if weekday, ok := parseWeekday(words[len(words)-1]); ok {
+			switch modifier {
+			case "", "this":
+				return today.AddDate(0, 0, (int(weekday)-int(today.Weekday())+7)%7), nil
+			case "next":
+				return today.AddDate(0, 0, (int(weekday)-int(today.Weekday())+6)%7+1), nil
+			case "last":
+				return today.AddDate(0, 0, -((int(today.Weekday())-int(weekday)+6)%7 + 1)), nil
+			}
+		}
+	}
+
+	if len(words) == 1 {
+		word := words[0]
+
+		switch {
+		case strings.HasPrefix(word, "+") || strings.HasPrefix(word, "-"):
+			return parseRelativeDate(word, today)
+		case strings.Count(word, "-") == 2:
+			parts := strings.Split(word, "-")
+			return dateOf(parts[0], parts[1], parts[2], today)
+		case strings.Count(word, "/") == 1:
+			parts := strings.Split(word, "/")
+			return dateOf(strconv.Itoa(today.Year()), parts[0], parts[1], today)
+		case strings.Count(word, "/") == 2:
+			parts := strings.Split(word, "/")
+			return dateOf(parts[2], parts[0], parts[1], today)
+		}
+	}
+
+	return today, fmt.Errorf("%q isn't a date, try %s", str, dateHelp)
+}
+
+// parseWeekday accepts a weekday's full name or any abbreviation of it at least three letters long
+func parseWeekday(str string) (time.Weekday, bool) {
+	if len(str) < 3 {
+		return time.Sunday, false
+	}
+
+	for name, weekday := range weekdays {
+		if strings.HasPrefix(name, str) {
+			return weekday, true
+		}
+	}
+
+	return time.Sunday, false
+}
+
+// parseRelativeDate parses an offset from today such as +2w or -10d
+func parseRelativeDate(str string, today time.Time) (time.Time, error) {
+	unit := str[len(str)-1]
+	count, err := strconv.Atoi(str[:len(str)-1])
+	if err != nil {
+		return today, fmt.Errorf("%q isn't an offset like +3d, +2w, +1m or -1y", str)
+	}
+
+	switch unit {
+	case 'd':
+		return today.AddDate(0, 0, count), nil
+	case 'w':
+		return today.AddDate(0, 0, 7*count), nil
+	case 'm':
+		return addMonths(today, count), nil
+	case 'y':
+		return addMonths(today, 12*count), nil
+	}
+
+	return today, fmt.Errorf("%q isn't an offset like +3d, +2w, +1m or -1y", str)
+}
+
+// addMonths moves the date by whole months, staying within the month landed on so that a month
+// after January 31 is the end of February rather than early March
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.Local)
+
+	day := date.Day()
+	if last := daysIn(first); day > last {
+		day = last
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
+
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.Local).Day()
+}
+
+// dateOf builds a date from its parts as typed, rejecting days the month doesn't have. Two digit
+// years are taken to be in this century.
+func dateOf(year string, month string, day string, today time.Time) (time.Time, error) {
+	y, yerr := strconv.Atoi(year)
+	m, merr := strconv.Atoi(month)
+	d, derr := strconv.Atoi(day)
+	if yerr != nil || merr != nil || derr != nil || y < 0 {
+		return today, fmt.Errorf("dates must be numbers like 2026-11-03 or 11/3")
+	}
+
+	if len(year) <= 2 {
+		y += 2000
+	}
+
+	if m < 1 || m > 12 {
+		return today, fmt.Errorf("%d isn't a month", m)
+	}
+
+	first := time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.Local)
+	if d < 1 {
+		return today, fmt.Errorf("%d isn't a day", d)
+	}
+	if d > daysIn(first) {
+		return today, fmt.Errorf("%s %d only has %d days", first.Month(), y, daysIn(first))
+	}
+
+	return first.AddDate(0, 0, d-1), nil
+}
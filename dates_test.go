@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	// a Sunday
+	now := time.Date(2026, time.October, 18, 15, 30, 0, 0, time.Local)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"today", date(2026, time.October, 18)},
+		{" Today ", date(2026, time.October, 18)},
+		{"tomorrow", date(2026, time.October, 19)},
+		{"yesterday", date(2026, time.October, 17)},
+		{"fri", date(2026, time.October, 23)},
+		{"this friday", date(2026, time.October, 23)},
+		{"next fri", date(2026, time.October, 23)},
+		{"last fri", date(2026, time.October, 16)},
+		{"sun", date(2026, time.October, 18)},
+		{"next sun", date(2026, time.October, 25)},
+		{"last sunday", date(2026, time.October, 11)},
+		{"+2w", date(2026, time.November, 1)},
+		{"-3d", date(2026, time.October, 15)},
+		{"+1m", date(2026, time.November, 18)},
+		{"+1y", date(2027, time.October, 18)},
+		{"2026-11-03", date(2026, time.November, 3)},
+		{"11/3", date(2026, time.November, 3)},
+		{"11/3/27", date(2027, time.November, 3)},
+		{"2/29/2028", date(2028, time.February, 29)},
+	}
+
+	for _, test := range tests {
+		got, err := parseDate(test.input, now)
+		if err != nil {
+			t.Errorf("parseDate(%q): %v", test.input, err)
+		} else if !got.Equal(test.want) {
+			t.Errorf("parseDate(%q) = %s, want %s", test.input, got.Format("2006-01-02"),
+				test.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestParseDateErrors(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 15, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"2/31", "February 2026 only has 28 days"},
+		{"2026-02-31", "February 2026 only has 28 days"},
+		{"2/29/27", "February 2027 only has 28 days"},
+		{"4/31", "April 2026 only has 30 days"},
+		{"13/1", "13 isn't a month"},
+		{"1/0", "0 isn't a day"},
+		{"+2x", `"+2x" isn't an offset like +3d, +2w, +1m or -1y`},
+		{"fr", `"fr" isn't a date, try ` + dateHelp},
+		{"someday", `"someday" isn't a date, try ` + dateHelp},
+		{"", "enter a date such as " + dateHelp},
+	}
+
+	for _, test := range tests {
+		_, err := parseDate(test.input, now)
+		if err == nil {
+			t.Errorf("parseDate(%q): expected an error", test.input)
+		} else if err.Error() != test.want {
+			t.Errorf("parseDate(%q) error = %q, want %q", test.input, err, test.want)
+		}
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		date   time.Time
+		months int
+		want   time.Time
+	}{
+		{time.Date(2026, time.January, 31, 0, 0, 0, 0, time.Local), 1,
+			time.Date(2026, time.February, 28, 0, 0, 0, 0, time.Local)},
+		{time.Date(2028, time.January, 31, 0, 0, 0, 0, time.Local), 1,
+			time.Date(2028, time.February, 29, 0, 0, 0, 0, time.Local)},
+		{time.Date(2026, time.March, 31, 0, 0, 0, 0, time.Local), -1,
+			time.Date(2026, time.February, 28, 0, 0, 0, 0, time.Local)},
+		{time.Date(2026, time.November, 15, 0, 0, 0, 0, time.Local), 2,
+			time.Date(2027, time.January, 15, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, test := range tests {
+		if got := addMonths(test.date, test.months); !got.Equal(test.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", test.date.Format("2006-01-02"), test.months,
+				got.Format("2006-01-02"), test.want.Format("2006-01-02"))
+		}
+	}
+}
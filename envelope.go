@@ -0,0 +1,117 @@
+package main
+
+import (
+	"time"
+)
+
+// Envelope is a monthly allowance for a category of variable spending such as groceries or fuel.
+// Whatever hasn't been spent yet in a month is forecasted as an expense.
+type Envelope struct {
+	Category  string
+	Allowance float32
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+// spent returns how much has been spent against the envelope in the month containing the given date,
+// based on the completed transactions in the account's history
+func (a *Account) spent(envelope *Envelope, month time.Time) float32 {
+	start := startOfMonth(month)
+	end := start.AddDate(0, 1, 0)
+
+	var total float32
+	for _, h := range a.History {
+		if h.Category != envelope.Category || h.Date.Before(start) || !h.Date.Before(end) {
+			continue
+		}
+
+		total -= h.Amount
+	}
+
+	return total
+}
+
+// remaining returns how much of the envelope's allowance is left to spend in the month containing
+// the given date
+func (a *Account) remaining(envelope *Envelope, month time.Time) float32 {
+	remaining := envelope.Allowance - a.spent(envelope, month)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+// predictEnvelope forecasts the unspent allowance of each month as a single expense. The rest of the
+// current month's allowance is expected today and future allowances on the first of their month,
+// which errs on the side of spending the money early.
+func (a *Account) predictEnvelope(envelope *Envelope, until time.Time) []Transaction {
+	transactions := []Transaction{}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	for month := startOfMonth(today); month.Before(until); month = month.AddDate(0, 1, 0) {
+		date := month
+		if date.Before(today) {
+			date = today
+		}
+
+		remaining := a.remaining(envelope, month)
+		if remaining == 0 || !date.Before(until) {
+			continue
+		}
+
+		t := Transaction{
+			date: date,
+			event: &Event{
+				ID:          "envelope " + envelope.Category,
+				Date:        date,
+				Description: envelope.Category + " budget",
+				Amount:      -remaining,
+				Frequency:   Once,
+				Category:    envelope.Category,
+			},
+			envelope: true,
+		}
+
+		t.calculateHash()
+		transactions = append(transactions, t)
+	}
+
+	return transactions
+}
+
+// logSpending records money spent against an envelope, which lowers the balance and whatever is
+// still forecasted for the envelope this month
+func (a *Account) logSpending(envelope *Envelope, amount float32, description string) {
+	if description == "" {
+		description = envelope.Category
+	}
+
+	now := time.Now()
+	a.Balance -= amount
+	a.History = append(a.History, HistoryEntry{
+		Date:        time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local),
+		Description: description,
+		Amount:      -amount,
+		Category:    envelope.Category,
+	})
+}
+
+func (a *Account) addEnvelope(envelope Envelope) {
+	for i := range a.Envelopes {
+		if a.Envelopes[i].Category == envelope.Category {
+			a.Envelopes[i].Allowance = envelope.Allowance
+			return
+		}
+	}
+
+	a.Envelopes = append(a.Envelopes, envelope)
+}
+
+func (a *Account) deleteEnvelope(i int) {
+	a.Envelopes = append(a.Envelopes[:i], a.Envelopes[i+1:]...)
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type EnvelopeViewKeyMap struct {
+	Add         key.Binding
+	Delete      key.Binding
+	LogSpending key.Binding
+	NextField   key.Binding
+	Help        key.Binding
+	Confirm     key.Binding
+	Cancel      key.Binding
+
+	LineUp     key.Binding
+	LineDown   key.Binding
+	GotoTop    key.Binding
+	GotoBottom key.Binding
+}
+
+func NewEnvelopeViewKeyMap() EnvelopeViewKeyMap {
+	k := EnvelopeViewKeyMap{
+		Add: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "add or change envelope"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "delete envelope"),
+		),
+		LogSpending: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "log spending"),
+		),
+		NextField: key.NewBinding(
+			key.WithKeys("tab", "shift+tab"),
+			key.WithHelp("tab", "next field"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "confirm"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+	}
+
+	settings.bind("envelope", &k)
+	return k
+}
+
+func (k EnvelopeViewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Add, k.LogSpending, k.Cancel}
+}
+
+func (k EnvelopeViewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.LineUp, k.LineDown, k.GotoTop, k.GotoBottom},
+		{k.Add, k.Delete, k.LogSpending},
+		{k.NextField, k.Confirm, k.Cancel},
+	}
+}
+
+type EnvelopeMode int
+
+const (
+	envelopeBrowse EnvelopeMode = iota
+	envelopeAdd
+	envelopeLog
+)
+
+type EnvelopeView struct {
+	keymap EnvelopeViewKeyMap
+	help   help.Model
+
+	table     table.Model
+	category  textinput.Model
+	allowance textinput.Model
+	spending  textinput.Model
+
+	mode    EnvelopeMode
+	account *Account
+	undo    *UndoHistory
+}
+
+func NewEnvelopeView(account *Account, undo *UndoHistory) EnvelopeView {
+	columns := []table.Column{
+		{Title: "Category", Width: 30},
+		{Title: "Allowance", Width: 15},
+		{Title: "Spent", Width: 15},
+		{Title: "Remaining", Width: 15},
+	}
+
+	style := table.DefaultStyles()
+	style.Header = style.Header.
+		Bold(false).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true)
+
+	style.Selected = style.Selected.
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground).
+		Bold(false)
+
+	t := table.New(
+		table.WithFocused(true),
+		table.WithColumns(columns),
+		table.WithStyles(style),
+	)
+
+	t.KeyMap.PageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.PageDown = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageDown = key.NewBinding(key.WithDisabled())
+
+	keymap := NewEnvelopeViewKeyMap()
+	t.KeyMap.LineUp = keymap.LineUp
+	t.KeyMap.LineDown = keymap.LineDown
+	t.KeyMap.GotoTop = keymap.GotoTop
+	t.KeyMap.GotoBottom = keymap.GotoBottom
+
+	category := textinput.New()
+	category.Prompt = "Category: "
+	category.Placeholder = "e.g. Groceries"
+
+	allowance := textinput.New()
+	allowance.Prompt = "Monthly allowance: $"
+	allowance.Placeholder = "400.00"
+	allowance.Validate = validateExpression
+
+	spending := textinput.New()
+	spending.Prompt = "Amount spent: $"
+	spending.Placeholder = "45.20"
+	spending.Validate = validateExpression
+
+	return EnvelopeView{
+		keymap: keymap,
+		help:   help.New(),
+
+		table:     t,
+		category:  category,
+		allowance: allowance,
+		spending:  spending,
+
+		mode:    envelopeBrowse,
+		account: account,
+		undo:    undo,
+	}
+}
+
+// browsing returns whether the view is showing the list of envelopes rather than taking input
+func (e *EnvelopeView) browsing() bool {
+	return e.mode == envelopeBrowse
+}
+
+func (e *EnvelopeView) regenerateRows() {
+	now := time.Now()
+
+	rows := make([]table.Row, 0, len(e.account.Envelopes))
+	for i := range e.account.Envelopes {
+		envelope := &e.account.Envelopes[i]
+		rows = append(rows, table.Row{
+			envelope.Category,
+			e.account.currency.FormatMoney(envelope.Allowance),
+			e.account.currency.FormatMoney(e.account.spent(envelope, now)),
+			e.account.currency.FormatMoney(e.account.remaining(envelope, now)),
+		})
+	}
+
+	e.table.SetHeight(len(rows))
+	e.table.SetRows(rows)
+}
+
+func (e *EnvelopeView) selected() *Envelope {
+	if len(e.account.Envelopes) == 0 {
+		return nil
+	}
+
+	return &e.account.Envelopes[e.table.Cursor()]
+}
+
+func (e *EnvelopeView) setCursorToCategory(category string) {
+	for i := range e.account.Envelopes {
+		if e.account.Envelopes[i].Category == category {
+			e.table.SetCursor(i)
+			return
+		}
+	}
+}
+
+// setAccount switches the envelopes to another account, either the real one or a scenario's sandbox
+func (e *EnvelopeView) setAccount(account *Account) {
+	e.account = account
+}
+
+func (e *EnvelopeView) browse() {
+	e.mode = envelopeBrowse
+	e.category.Blur()
+	e.allowance.Blur()
+	e.spending.Blur()
+	e.table.Focus()
+	e.regenerateRows()
+}
+
+func (e *EnvelopeView) View() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Budget envelopes for %s", time.Now().Format("January 2006")))
+	b.WriteString("\n\n")
+	b.WriteString(e.table.View())
+	b.WriteString("\n\n")
+
+	switch e.mode {
+	case envelopeAdd:
+		b.WriteString(e.category.View())
+		b.WriteString("\n")
+		b.WriteString(e.allowance.View())
+		b.WriteString("\n\n")
+	case envelopeLog:
+		if envelope := e.selected(); envelope != nil {
+			b.WriteString(envelope.Category)
+			b.WriteString("\n")
+		}
+		b.WriteString(e.spending.View())
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(e.help.View(e.keymap))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (e *EnvelopeView) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		e.help.Width = msg.Width
+	case tea.MouseMsg:
+		if e.mode == envelopeBrowse {
+			handleTableMouse(&e.table, e.View, msg)
+		}
+	case tea.KeyMsg:
+		switch e.mode {
+		case envelopeBrowse:
+			return e.handleTableInput(msg)
+		case envelopeAdd:
+			return e.handleAddInput(msg)
+		case envelopeLog:
+			return e.handleLogInput(msg)
+		}
+	}
+
+	return nil
+}
+
+func (e *EnvelopeView) handleTableInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, e.keymap.Help):
+		e.help.ShowAll = !e.help.ShowAll
+	case key.Matches(msg, e.keymap.Add):
+		e.mode = envelopeAdd
+		e.table.Blur()
+		e.category.Reset()
+		e.allowance.Reset()
+		if envelope := e.selected(); envelope != nil {
+			e.category.SetValue(envelope.Category)
+			e.allowance.SetValue(fmt.Sprintf("%.02f", envelope.Allowance))
+		}
+		e.category.Focus()
+		return nil
+	case key.Matches(msg, e.keymap.LogSpending):
+		if e.selected() == nil {
+			return nil
+		}
+
+		e.mode = envelopeLog
+		e.table.Blur()
+		e.spending.Reset()
+		e.spending.Focus()
+		return nil
+	case key.Matches(msg, e.keymap.Delete):
+		if e.selected() != nil {
+			e.undo.remember(e.account)
+			e.account.deleteEnvelope(e.table.Cursor())
+			if e.table.Cursor() >= len(e.account.Envelopes) && e.table.Cursor() > 0 {
+				e.table.SetCursor(e.table.Cursor() - 1)
+			}
+			e.regenerateRows()
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	e.table, cmd = e.table.Update(msg)
+	return cmd
+}
+
+func (e *EnvelopeView) handleAddInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, e.keymap.Cancel):
+		e.browse()
+		return nil
+	case key.Matches(msg, e.keymap.NextField):
+		if e.category.Focused() {
+			e.category.Blur()
+			e.allowance.Focus()
+		} else {
+			e.allowance.Blur()
+			e.category.Focus()
+		}
+		return nil
+	case key.Matches(msg, e.keymap.Confirm):
+		category := strings.TrimSpace(e.category.Value())
+		allowance, err := evaluate(e.allowance.Value())
+		if category != "" && err == nil && allowance > 0 {
+			e.undo.remember(e.account)
+			e.account.addEnvelope(Envelope{
+				Category:  category,
+				Allowance: float32(allowance),
+			})
+		}
+
+		e.browse()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	if e.category.Focused() {
+		e.category, cmd = e.category.Update(msg)
+	} else {
+		e.allowance, cmd = e.allowance.Update(msg)
+	}
+	return cmd
+}
+
+func (e *EnvelopeView) handleLogInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, e.keymap.Cancel):
+		e.browse()
+		return nil
+	case key.Matches(msg, e.keymap.Confirm):
+		amount, err := evaluate(e.spending.Value())
+		if envelope := e.selected(); envelope != nil && err == nil && amount != 0 {
+			e.undo.remember(e.account)
+			e.account.logSpending(envelope, float32(amount), "")
+		}
+
+		e.browse()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	e.spending, cmd = e.spending.Update(msg)
+	return cmd
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 )
 
@@ -35,10 +37,54 @@ func (f Frequency) toString() string {
 }
 
 type Event struct {
+	// identifies the event for as long as it exists, however it's changed
+	ID string `json:",omitempty"`
+
 	Date        time.Time
 	Description string
 	Amount      float32
 	Frequency   Frequency
+	Category    string   `json:",omitempty"`
+	Tags        []string `json:",omitempty"`
+
+	// how much the amount may vary between occurrences, used when simulating the forecast
+	Distribution *Distribution `json:",omitempty"`
+
+	// name of the savings goal this event contributes to
+	Goal string `json:",omitempty"`
+
+	// account that the other side of this event is posted to when exporting to plain text accounting
+	LedgerAccount string `json:",omitempty"`
+
+	// paused events are kept but not projected into the forecast until they resume on ResumeOn, or at
+	// all when it isn't set
+	Paused   bool       `json:",omitempty"`
+	ResumeOn *time.Time `json:",omitempty"`
+
+	// last day a repeating event may occur on, it repeats forever when not set
+	Until *time.Time `json:",omitempty"`
+}
+
+const uncategorized = "Uncategorized"
+
+func (e *Event) category() string {
+	if e.Category != "" {
+		return e.Category
+	}
+
+	return uncategorized
+}
+
+func (e *Event) ledgerAccount() string {
+	if e.LedgerAccount != "" {
+		return e.LedgerAccount
+	}
+
+	if e.Amount > 0 {
+		return "income:unknown"
+	}
+
+	return "expenses:unknown"
 }
 
 func (e *Event) nextOccurrence(from time.Time) time.Time {
@@ -59,22 +105,96 @@ func (e *Event) nextOccurrence(from time.Time) time.Time {
 	return e.Date.AddDate(100, 0, 0)
 }
 
+// monthlyAmount returns what the event averages out to per month. Events which don't repeat have no
+// monthly equivalent.
+func (e *Event) monthlyAmount() float32 {
+	switch e.Frequency {
+	case Daily:
+		return e.Amount * 365 / 12
+	case Weekly:
+		return e.Amount * 52 / 12
+	case Biweekly:
+		return e.Amount * 26 / 12
+	case Monthly:
+		return e.Amount
+	case Yearly:
+		return e.Amount / 12
+	}
+
+	return 0
+}
+
+func newEventID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// assignEventIDs gives an ID to every event that doesn't have one yet, such as those saved before
+// events had IDs
+func assignEventIDs(events []Event) {
+	for i := range events {
+		if events[i].ID == "" {
+			events[i].ID = newEventID()
+		}
+	}
+}
+
 func (e *Event) predict(until time.Time) []Transaction {
+	return e.occurrences(until, false)
+}
+
+// occurrences returns the transactions of the event up to the given date, either those that happen
+// or those that are skipped while the event is paused
+func (e *Event) occurrences(until time.Time, paused bool) []Transaction {
 	transactions := []Transaction{}
 
 	now := e.Date
-	for now.Before(until) {
-		t := Transaction{
-			date:  now,
-			event: e,
-		}
+	for now.Before(until) && (e.Until == nil || !now.After(*e.Until)) {
+		if e.pausedOn(now) == paused {
+			t := Transaction{
+				date:   now,
+				event:  e,
+				paused: paused,
+			}
 
-		t.calculateHash()
-		transactions = append(transactions, t)
+			t.calculateHash()
+			transactions = append(transactions, t)
+		}
 
 		now = e.nextOccurrence(now)
 	}
 
 	return transactions
+}
+
+// ended returns whether the event has no occurrences left before its Until date
+func (e *Event) ended() bool {
+	return e.Until != nil && e.Date.After(*e.Until)
+}
+
+// resumeIfDue clears a pause that is over now the event has moved past its resume date
+func (e *Event) resumeIfDue() {
+	if e.ResumeOn != nil && !e.Date.Before(*e.ResumeOn) {
+		e.Paused = false
+		e.ResumeOn = nil
+	}
+}
+
+func (e *Event) pausedOn(date time.Time) bool {
+	return e.Paused && (e.ResumeOn == nil || date.Before(*e.ResumeOn))
+}
+
+// firstOccurrence returns the date of the event's first occurrence that isn't skipped by a pause
+func (e *Event) firstOccurrence() time.Time {
+	date := e.Date
+	if e.Paused && e.ResumeOn == nil {
+		return date
+	}
+
+	for e.pausedOn(date) {
+		date = e.nextOccurrence(date)
+	}
 
+	return date
 }
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type EventListViewKeyMap struct {
+	Edit      key.Binding
+	Delete    key.Binding
+	Duplicate key.Binding
+	Pause     key.Binding
+	Sort      key.Binding
+	Help      key.Binding
+	Cancel    key.Binding
+
+	LineUp     key.Binding
+	LineDown   key.Binding
+	GotoTop    key.Binding
+	GotoBottom key.Binding
+}
+
+func NewEventListViewKeyMap() EventListViewKeyMap {
+	k := EventListViewKeyMap{
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit event"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "delete event"),
+		),
+		Duplicate: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "duplicate event"),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "pause/resume event"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "change sort order"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back to forecast"),
+		),
+
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+	}
+
+	settings.bind("events", &k)
+	return k
+}
+
+func (k EventListViewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Edit, k.Sort, k.Cancel}
+}
+
+func (k EventListViewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.LineUp, k.LineDown, k.GotoTop, k.GotoBottom},
+		{k.Edit, k.Delete, k.Duplicate, k.Pause},
+		{k.Sort, k.Cancel},
+	}
+}
+
+type EventSort int
+
+const (
+	sortByDate EventSort = iota
+	sortByDescription
+	sortByAmount
+	sortByMonthly
+	sortSentinel
+)
+
+func (s EventSort) toString() string {
+	switch s {
+	case sortByDate:
+		return "next date"
+	case sortByDescription:
+		return "description"
+	case sortByAmount:
+		return "amount"
+	case sortByMonthly:
+		return "monthly cost"
+	}
+
+	return "unknown"
+}
+
+// EventListView lists every event in the account, including those beyond the forecast horizon
+type EventListView struct {
+	keymap EventListViewKeyMap
+	help   help.Model
+
+	table   table.Model
+	columns []table.Column
+
+	account      *Account
+	undo         *UndoHistory
+	sort         EventSort
+	height       int
+	confirmation Confirmation
+	pause        PausePrompt
+
+	// order[i] is the index into the account's events of row i
+	order []int
+}
+
+func NewEventListView(account *Account, undo *UndoHistory) EventListView {
+	columns := []table.Column{
+		{Title: "Description", Width: 35},
+		{Title: "Frequency", Width: 10},
+		{Title: "Next date", Width: 20},
+		{Title: "Amount", Width: 15},
+		{Title: "Monthly", Width: 15},
+		{Title: "Category", Width: 20},
+		{Title: "Status", Width: 18},
+	}
+
+	style := table.DefaultStyles()
+	style.Header = style.Header.
+		Bold(false).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true)
+
+	style.Selected = style.Selected.
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground).
+		Bold(false)
+
+	t := table.New(
+		table.WithFocused(true),
+		table.WithColumns(columns),
+		table.WithStyles(style),
+	)
+
+	t.KeyMap.PageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.PageDown = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageDown = key.NewBinding(key.WithDisabled())
+
+	keymap := NewEventListViewKeyMap()
+	t.KeyMap.LineUp = keymap.LineUp
+	t.KeyMap.LineDown = keymap.LineDown
+	t.KeyMap.GotoTop = keymap.GotoTop
+	t.KeyMap.GotoBottom = keymap.GotoBottom
+
+	return EventListView{
+		keymap: keymap,
+		help:   help.New(),
+
+		table:        t,
+		columns:      columns,
+		account:      account,
+		undo:         undo,
+		confirmation: NewConfirmation(),
+		pause:        NewPausePrompt(),
+		height:       defaultHeight,
+	}
+}
+
+// setAccount switches the list to another account, either the real one or a scenario's sandbox
+func (e *EventListView) setAccount(account *Account) {
+	e.account = account
+}
+
+func (e *EventListView) regenerateRows() {
+	events := e.account.Events
+
+	e.order = make([]int, len(events))
+	for i := range e.order {
+		e.order[i] = i
+	}
+
+	sort.SliceStable(e.order, func(x int, y int) bool {
+		a := &events[e.order[x]]
+		b := &events[e.order[y]]
+
+		switch e.sort {
+		case sortByDescription:
+			return strings.ToLower(a.Description) < strings.ToLower(b.Description)
+		case sortByAmount:
+			return a.Amount < b.Amount
+		case sortByMonthly:
+			return a.monthlyAmount() < b.monthlyAmount()
+		}
+
+		return a.Date.Before(b.Date)
+	})
+
+	rows := make([]table.Row, 0, len(events))
+	for _, i := range e.order {
+		event := &events[i]
+
+		var monthly string
+		if event.Frequency != Once {
+			monthly = e.account.currency.FormatMoney(event.monthlyAmount())
+		}
+
+		var status string
+		switch {
+		case event.Paused && event.ResumeOn != nil:
+			status = event.ResumeOn.Format("Resumes Jan 2 2006")
+		case event.Paused:
+			status = "Paused"
+		case event.ended():
+			status = "Ended"
+		case event.Until != nil:
+			status = event.Until.Format("Until Jan 2 2006")
+		}
+
+		row := table.Row{
+			event.Description,
+			event.Frequency.toString(),
+			event.Date.Format("January 2, 2006"),
+			e.account.currency.FormatMoney(event.Amount),
+			monthly,
+			event.category(),
+			status,
+		}
+
+		if event.Paused {
+			for j := range row {
+				row[j] = faint(row[j], e.columns[j].Width)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	e.table.SetHeight(e.tableHeight(len(rows)))
+	e.table.SetRows(rows)
+	e.table.SetCursor(e.table.Cursor())
+}
+
+// tableHeight returns how many rows fit on screen alongside the title, prompts and help
+func (e *EventListView) tableHeight(rows int) int {
+	// the title and a blank line above the table, and a blank line below it
+	lines := 2 + tableHeaderLines + 1
+	if e.confirmation.active() {
+		lines += lipgloss.Height(e.confirmation.View()) + 1
+	}
+	if e.pause.active() {
+		lines += lipgloss.Height(e.pause.View()) + 1
+	}
+	lines += lipgloss.Height(e.help.View(e.keymap))
+
+	return fitRows(rows, e.height, lines)
+}
+
+// browsing returns whether the view is showing the list rather than asking for confirmation
+func (e *EventListView) browsing() bool {
+	return !e.confirmation.active() && !e.pause.active()
+}
+
+// getSelectedEvent returns the event on the selected row, or nil if there are no events
+func (e *EventListView) getSelectedEvent() *Event {
+	if i := e.selectedIndex(); i >= 0 {
+		return &e.account.Events[i]
+	}
+
+	return nil
+}
+
+func (e *EventListView) selectedIndex() int {
+	if len(e.order) == 0 {
+		return -1
+	}
+
+	return e.order[e.table.Cursor()]
+}
+
+// setCursorToEvent moves the cursor to the row showing event i
+func (e *EventListView) setCursorToEvent(i int) {
+	for row, index := range e.order {
+		if index == i {
+			e.table.SetCursor(row)
+			return
+		}
+	}
+}
+
+// handleMouse selects the clicked event and scrolls the table with the wheel
+func (e *EventListView) handleMouse(msg tea.MouseMsg) {
+	if e.browsing() {
+		handleTableMouse(&e.table, e.View, msg)
+	}
+}
+
+func (e *EventListView) View() string {
+	var b strings.Builder
+	b.WriteString("Events sorted by ")
+	b.WriteString(e.sort.toString())
+	b.WriteString("\n\n")
+	b.WriteString(e.table.View())
+	b.WriteString("\n\n")
+	if e.confirmation.active() {
+		b.WriteString(e.confirmation.View())
+		b.WriteString("\n\n")
+	}
+	if e.pause.active() {
+		b.WriteString(e.pause.View())
+		b.WriteString("\n\n")
+	}
+	b.WriteString(e.help.View(e.keymap))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (e *EventListView) Update(msg tea.Msg) tea.Cmd {
+	if handled, cmd := e.confirmation.Update(msg); handled {
+		e.regenerateRows()
+		return cmd
+	}
+	if handled, cmd := e.pause.Update(msg); handled {
+		e.regenerateRows()
+		return cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		e.help.Width = msg.Width
+		e.height = msg.Height
+		e.regenerateRows()
+		return nil
+	case tea.MouseMsg:
+		e.handleMouse(msg)
+		return nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, e.keymap.Help):
+			e.help.ShowAll = !e.help.ShowAll
+			e.regenerateRows()
+			return nil
+		case key.Matches(msg, e.keymap.Sort):
+			e.sort = (e.sort + 1) % sortSentinel
+			e.regenerateRows()
+			e.table.SetCursor(0)
+			return nil
+		}
+
+		i := e.selectedIndex()
+		if i < 0 {
+			return nil
+		}
+
+		switch {
+		case key.Matches(msg, e.keymap.Delete):
+			event := e.account.Events[i]
+
+			prompt := fmt.Sprintf("Delete %s?", event.Description)
+			if event.Frequency != Once {
+				prompt = fmt.Sprintf("Delete %s and every future occurrence?", event.Description)
+			}
+
+			cmd := e.confirmation.ask(prompt, func() tea.Cmd {
+				e.undo.remember(e.account)
+				e.account.deleteEvent(i)
+				e.regenerateRows()
+				return status("Deleted %s", event.Description)
+			})
+			e.regenerateRows()
+			return cmd
+		case key.Matches(msg, e.keymap.Duplicate):
+			e.undo.remember(e.account)
+			e.account.duplicateEvent(i)
+			e.regenerateRows()
+			e.setCursorToEvent(len(e.account.Events) - 1)
+			return nil
+		case key.Matches(msg, e.keymap.Pause):
+			cmd := e.pause.toggle(e.account, i, func() { e.undo.remember(e.account) })
+			e.regenerateRows()
+			return cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	e.table, cmd = e.table.Update(msg)
+	return cmd
+}
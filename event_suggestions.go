@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fsareshwala/forecash/selection"
+)
+
+// setAccount sets the account whose descriptions are suggested and whose templates can be chosen
+func (e *EventView) setAccount(account *Account) {
+	e.account = account
+	e.suggestions = account.descriptionSuggestions()
+
+	descriptions := make([]string, len(e.suggestions))
+	for i := range e.suggestions {
+		descriptions[i] = e.suggestions[i].Description
+	}
+	e.inputs[description].SetSuggestions(descriptions)
+}
+
+// suggestion returns the first description used before that starts with what has been typed, or nil
+func (e *EventView) suggestion() *EventTemplate {
+	typed := strings.ToLower(e.inputs[description].Value())
+	if typed == "" {
+		return nil
+	}
+
+	for i := range e.suggestions {
+		if strings.HasPrefix(strings.ToLower(e.suggestions[i].Description), typed) {
+			return &e.suggestions[i]
+		}
+	}
+
+	return nil
+}
+
+// applyTemplate fills in everything but the date from the template
+func (e *EventView) applyTemplate(template *EventTemplate) {
+	e.inputs[description].SetValue(template.Description)
+	e.inputs[description].CursorEnd()
+	e.inputs[amount].SetValue(fmt.Sprintf("%.02f", template.Amount))
+	e.inputs[variation].SetValue(template.Distribution.toString())
+	e.inputs[category].SetValue(template.Category)
+	e.inputs[tags].SetValue(strings.Join(template.Tags, ", "))
+	e.repeat.SetSelected(int(template.Frequency))
+}
+
+// template builds a template from the form, reporting what's wrong with it if it can't
+func (e *EventView) template() (EventTemplate, error) {
+	name := strings.TrimSpace(e.inputs[description].Value())
+	if name == "" {
+		return EventTemplate{}, fmt.Errorf("a template needs a description")
+	}
+
+	value, err := evaluate(e.inputs[amount].Value())
+	if err != nil {
+		return EventTemplate{}, err
+	}
+
+	distribution, _ := parseDistribution(e.inputs[variation].Value())
+	return EventTemplate{
+		Description:  name,
+		Amount:       float32(value),
+		Frequency:    Frequency(e.repeat.Selected()),
+		Category:     strings.TrimSpace(e.inputs[category].Value()),
+		Tags:         parseTags(e.inputs[tags].Value()),
+		Distribution: distribution,
+	}, nil
+}
+
+// choosingTemplate returns whether the list of templates is shown instead of the form
+func (e *EventView) choosingTemplate() bool {
+	return e.choosing
+}
+
+func (e *EventView) chooseTemplate() tea.Cmd {
+	templates := e.account.templates()
+	if len(templates) == 0 {
+		return status("No templates yet, fill in an event and press %s to save one",
+			e.keymap.SaveTemplate.Help().Key)
+	}
+
+	names := make([]string, len(templates))
+	for i := range templates {
+		names[i] = fmt.Sprintf("%-30s %12s  %s", templates[i].Description,
+			e.account.currency.FormatMoney(templates[i].Amount), templates[i].Frequency.toString())
+	}
+
+	e.templates = selection.New(names)
+	e.templates.SelectedStyle = lipgloss.NewStyle().Foreground(theme.Accent)
+	e.templates.TextStyle = lipgloss.NewStyle().Foreground(theme.Muted)
+	settings.bind("selection", &e.templates.KeyMap)
+	e.templates.Focus()
+	e.choosing = true
+	return nil
+}
+
+func (e *EventView) handleTemplateInput(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.MouseMsg); ok {
+		e.handleMouse(msg)
+		return nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		templates := e.account.templates()
+		i := e.templates.Selected()
+
+		switch {
+		case key.Matches(msg, e.keymap.Cancel):
+			e.choosing = false
+			return nil
+		case key.Matches(msg, e.keymap.Confirm):
+			e.applyTemplate(&templates[i])
+			e.choosing = false
+			return nil
+		case key.Matches(msg, e.keymap.DeleteTemplate):
+			name := templates[i].Description
+			e.account.deleteTemplate(i)
+			e.setAccount(e.account)
+
+			e.choosing = false
+			if len(e.account.templates()) > 0 {
+				e.chooseTemplate()
+				e.templates.SetSelected(i % len(e.account.templates()))
+			}
+			return status("Deleted template %s", name)
+		}
+	}
+
+	e.templates, _ = e.templates.Update(msg)
+	return nil
+}
+
+func (e *EventView) saveTemplate() tea.Cmd {
+	template, err := e.template()
+	if err != nil {
+		return statusError("Save template", err)
+	}
+
+	e.account.saveTemplate(template)
+	e.setAccount(e.account)
+	return status("Saved template %s", template.Description)
+}
+
+// suggestionView describes what using the suggested description would fill in
+func (e *EventView) suggestionView() string {
+	suggestion := e.suggestion()
+	if suggestion == nil || suggestion.Description == e.inputs[description].Value() ||
+		!e.inputs[description].Focused() {
+		return ""
+	}
+
+	text := fmt.Sprintf("%s %s: %s %s", e.keymap.Complete.Help().Key, suggestion.Description,
+		e.account.currency.FormatMoney(suggestion.Amount),
+		strings.ToLower(suggestion.Frequency.toString()))
+	if suggestion.Category != "" {
+		text += ", " + suggestion.Category
+	}
+
+	return lipgloss.NewStyle().Foreground(theme.Muted).Render(text)
+}
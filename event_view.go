@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -15,15 +14,19 @@ import (
 )
 
 type EventViewKeyMap struct {
-	PreviousField key.Binding
-	NextField     key.Binding
-	Help          key.Binding
-	Confirm       key.Binding
-	Cancel        key.Binding
+	PreviousField  key.Binding
+	NextField      key.Binding
+	Complete       key.Binding
+	Templates      key.Binding
+	SaveTemplate   key.Binding
+	DeleteTemplate key.Binding
+	Help           key.Binding
+	Confirm        key.Binding
+	Cancel         key.Binding
 }
 
 func NewEventViewKeyMap() EventViewKeyMap {
-	return EventViewKeyMap{
+	k := EventViewKeyMap{
 		PreviousField: key.NewBinding(
 			key.WithKeys("shift+tab"),
 			key.WithHelp("shift+tab", "previous field"),
@@ -32,6 +35,22 @@ func NewEventViewKeyMap() EventViewKeyMap {
 			key.WithKeys("tab"),
 			key.WithHelp("tab", "next field"),
 		),
+		Complete: key.NewBinding(
+			key.WithKeys("right"),
+			key.WithHelp("→", "use suggested description"),
+		),
+		Templates: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "choose template"),
+		),
+		SaveTemplate: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "save as template"),
+		),
+		DeleteTemplate: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "delete template"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "toggle help"),
@@ -45,15 +64,19 @@ func NewEventViewKeyMap() EventViewKeyMap {
 			key.WithHelp("esc", "cancel"),
 		),
 	}
+
+	settings.bind("event", &k)
+	return k
 }
 
 func (k EventViewKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Confirm, k.Cancel}
+	return []key.Binding{k.Help, k.Templates, k.Confirm, k.Cancel}
 }
 
 func (k EventViewKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.PreviousField, k.NextField},
+		{k.PreviousField, k.NextField, k.Complete},
+		{k.Templates, k.SaveTemplate, k.DeleteTemplate},
 		{k.Confirm, k.Cancel},
 	}
 }
@@ -61,11 +84,12 @@ func (k EventViewKeyMap) FullHelp() [][]key.Binding {
 type FocusedField int
 
 const (
-	month FocusedField = iota
-	day
-	year
+	date FocusedField = iota
 	description
 	amount
+	variation
+	category
+	tags
 	repeat
 	sentinel
 )
@@ -79,41 +103,55 @@ type EventView struct {
 
 	focused FocusedField
 	event   *Event
+
+	// whether event is a copy to be added rather than an event in the account being edited
+	copied bool
+
+	// descriptions used before, offered as the description is typed
+	account     *Account
+	suggestions []EventTemplate
+
+	// while choosing a template the form is replaced by the list of templates
+	choosing  bool
+	templates selection.Model
 }
 
 func NewEventView() EventView {
-	now := time.Now()
 	inputs := make([]textinput.Model, sentinel)
 
-	inputs[month] = textinput.New()
-	inputs[month].Placeholder = fmt.Sprintf("%02d ", now.Month())
-	inputs[month].CharLimit = 2
-	inputs[month].Width = 2
-	inputs[month].Prompt = ""
-	inputs[month].Validate = validateMonth
-
-	inputs[day] = textinput.New()
-	inputs[day].Placeholder = fmt.Sprintf("%02d ", now.Day())
-	inputs[day].CharLimit = 2
-	inputs[day].Width = 2
-	inputs[day].Prompt = ""
-	inputs[day].Validate = validateDay
-
-	inputs[year] = textinput.New()
-	inputs[year].Placeholder = fmt.Sprintf("%d", now.Year())
-	inputs[year].CharLimit = 4
-	inputs[year].Width = 4
-	inputs[year].Prompt = ""
-	inputs[year].Validate = validateInteger
+	// the date isn't validated as it's typed since most dates only make sense once finished, the
+	// parsed date or what's wrong with it is shown underneath instead
+	inputs[date] = textinput.New()
+	inputs[date].Placeholder = dateHelp
+	inputs[date].Prompt = ""
 
+	// suggestions are accepted by the view itself so that the other fields are filled in too, and only
+	// the first one is offered so that it's the one described underneath
 	inputs[description] = textinput.New()
 	inputs[description].Placeholder = "Enter a description..."
 	inputs[description].Prompt = ""
+	inputs[description].ShowSuggestions = true
+	inputs[description].KeyMap.AcceptSuggestion = key.NewBinding(key.WithDisabled())
+	inputs[description].KeyMap.NextSuggestion = key.NewBinding(key.WithDisabled())
+	inputs[description].KeyMap.PrevSuggestion = key.NewBinding(key.WithDisabled())
 
 	inputs[amount] = textinput.New()
 	inputs[amount].Placeholder = "123.45"
 	inputs[amount].Prompt = "$"
-	inputs[amount].Validate = validateFloat
+	inputs[amount].Validate = validateExpression
+
+	inputs[variation] = textinput.New()
+	inputs[variation].Placeholder = "optional, a range like 80..120 or a deviation like ±15"
+	inputs[variation].Prompt = ""
+	inputs[variation].Validate = validateDistribution
+
+	inputs[category] = textinput.New()
+	inputs[category].Placeholder = "e.g. Groceries"
+	inputs[category].Prompt = ""
+
+	inputs[tags] = textinput.New()
+	inputs[tags].Placeholder = "comma separated, e.g. car, insurance"
+	inputs[tags].Prompt = ""
 
 	repeat := selection.New([]string{
 		Once.toString(),
@@ -123,6 +161,8 @@ func NewEventView() EventView {
 		Monthly.toString(),
 		Yearly.toString(),
 	})
+	repeat.TextStyle = lipgloss.NewStyle().Foreground(theme.Muted)
+	settings.bind("selection", &repeat.KeyMap)
 
 	return EventView{
 		keymap: NewEventViewKeyMap(),
@@ -135,7 +175,7 @@ func NewEventView() EventView {
 }
 
 func (e *EventView) hasEvent() bool {
-	return e.event != nil
+	return e.event != nil && !e.copied
 }
 
 func (e *EventView) getEvent() *Event {
@@ -148,33 +188,47 @@ func (e *EventView) getEvent() *Event {
 		event = e.event
 	}
 
-	// the textinput validator already ensures that the number is valid so no need to check for errors
-	input_month, _ := strconv.ParseInt(e.inputs[month].Value(), 10, 8)
-	input_day, _ := strconv.ParseInt(e.inputs[day].Value(), 10, 8)
-	input_year, _ := strconv.ParseInt(e.inputs[year].Value(), 10, 16)
-	input_amount, _ := strconv.ParseFloat(e.inputs[amount].Value(), 32)
+	// callers check validate first, so the date and amount have already been parsed successfully
+	input_amount, _ := evaluate(e.inputs[amount].Value())
 	input_repeat := Frequency(e.repeat.Selected())
 
-	new_month := time.Month(input_month)
-	new_day := int(input_day)
-	new_year := int(input_year)
 	new_amount := float32(input_amount)
 
-	event.Date = time.Date(new_year, new_month, new_day, 0, 0, 0, 0, time.Local)
+	event.Date, _ = parseDate(e.inputs[date].Value(), time.Now())
 	event.Description = e.inputs[description].Value()
 	event.Amount = new_amount
 	event.Frequency = input_repeat
+	event.Distribution, _ = parseDistribution(e.inputs[variation].Value())
+	event.Category = strings.TrimSpace(e.inputs[category].Value())
+	event.Tags = parseTags(e.inputs[tags].Value())
 
 	return event
 }
 
 func (e *EventView) setEvent(event *Event) {
+	e.fill(event)
 	e.event = event
-	e.inputs[month].SetValue(fmt.Sprintf("%02d", event.Date.Month()))
-	e.inputs[day].SetValue(fmt.Sprintf("%02d", event.Date.Day()))
-	e.inputs[year].SetValue(fmt.Sprintf("%d", event.Date.Year()))
+	e.copied = false
+}
+
+// copyEvent starts a new event from a copy of the given one, occurring first on the given date
+func (e *EventView) copyEvent(event *Event, from time.Time) {
+	copied := copyEvents([]Event{*event})[0]
+	copied.Date = from
+
+	e.fill(&copied)
+	e.event = &copied
+	e.copied = true
+}
+
+func (e *EventView) fill(event *Event) {
+	e.choosing = false
+	e.inputs[date].SetValue(event.Date.Format("2006-01-02"))
 	e.inputs[description].SetValue(event.Description)
 	e.inputs[amount].SetValue(fmt.Sprintf("%.02f", event.Amount))
+	e.inputs[variation].SetValue(event.Distribution.toString())
+	e.inputs[category].SetValue(event.Category)
+	e.inputs[tags].SetValue(strings.Join(event.Tags, ", "))
 	e.repeat.SetSelected(int(event.Frequency))
 
 	e.focused = description
@@ -183,92 +237,163 @@ func (e *EventView) setEvent(event *Event) {
 
 func (e *EventView) unsetEvent() {
 	e.event = nil
+	e.copied = false
+	e.choosing = false
 	for i := range e.inputs {
 		e.inputs[i].Reset()
 	}
 	e.repeat.Reset()
 
-	now := time.Now()
-	e.inputs[month].SetValue(fmt.Sprintf("%d", now.Month()))
-	e.inputs[day].SetValue(fmt.Sprintf("%d", now.Day()))
-	e.inputs[year].SetValue(fmt.Sprintf("%d", now.Year()))
+	e.inputs[date].SetValue("today")
 
 	e.focused = description
 	e.focus()
 }
 
-func validateMonth(str string) error {
-	// The textinput will already ensure that the number is of the correct length
-	result, err := strconv.ParseInt(str, 10, 8)
-	if err != nil {
-		return err
+func parseTags(str string) []string {
+	var result []string
+	for _, tag := range strings.Split(str, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			result = append(result, tag)
+		}
 	}
 
-	if result < 1 || result > 12 {
-		return fmt.Errorf("month is invalid")
+	return result
+}
+
+func validateDistribution(str string) error {
+	// Allow the beginning of a deviation or the first half of a range to be typed
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(str, "."), ".")
+	if str == "±" || str == "+" || str == "+-" || trimmed != str {
+		return nil
 	}
 
-	return nil
+	_, err := parseDistribution(str)
+	return err
 }
 
-func validateDay(str string) error {
-	// The textinput will already ensure that the number is of the correct length
-	result, err := strconv.ParseInt(str, 10, 8)
-	if err != nil {
+// validate returns what's wrong with the fields that can't be checked as they're typed, moving the
+// focus to the first such field
+func (e *EventView) validate() error {
+	if _, err := parseDate(e.inputs[date].Value(), time.Now()); err != nil {
+		e.focused = date
+		e.focus()
 		return err
 	}
 
-	if result < 1 || result > 31 {
-		return fmt.Errorf("day is invalid")
+	if _, err := evaluate(e.inputs[amount].Value()); err != nil {
+		e.focused = amount
+		e.focus()
+		return err
 	}
 
 	return nil
 }
 
-func validateInteger(str string) error {
-	// The textinput will already ensure that the number is of the correct length
-	_, err := strconv.ParseInt(str, 10, 16)
-	return err
+// validateExpression only rejects characters that can't be part of an amount, since an unfinished
+// expression such as 2*45.50+ isn't valid until the rest of it is typed
+func validateExpression(str string) error {
+	for _, r := range str {
+		if !strings.ContainsRune("0123456789.,$+-*/() ", r) {
+			return fmt.Errorf("amounts may only contain numbers, + - * / and parentheses")
+		}
+	}
+
+	return nil
 }
 
-func validateFloat(str string) error {
-	// Allow the beginning of a negative number
-	if str == "-" {
-		return nil
+// amountPreview shows what the amount expression works out to, or what's wrong with it
+func (e *EventView) amountPreview() string {
+	value := e.inputs[amount].Value()
+	result, err := evaluate(value)
+	if err != nil {
+		if value == "" {
+			return ""
+		}
+		return lipgloss.NewStyle().Foreground(theme.Negative).Render(err.Error())
 	}
 
-	// The textinput will already ensure that the number is of the correct length
-	_, err := strconv.ParseFloat(str, 32)
-	return err
+	return lipgloss.NewStyle().Foreground(theme.Muted).Render(fmt.Sprintf("= %.2f", result))
+}
+
+// sections renders each field with its label and anything shown underneath it, in the order the
+// fields are focused
+func (e *EventView) sections() []string {
+	style := lipgloss.NewStyle().Foreground(theme.Accent)
+	sections := make([]string, sentinel)
+
+	var preview string
+	if parsed, err := parseDate(e.inputs[date].Value(), time.Now()); err != nil {
+		preview = lipgloss.NewStyle().Foreground(theme.Negative).Render(err.Error())
+	} else {
+		preview = parsed.Format("Monday, January 2, 2006")
+		preview = lipgloss.NewStyle().Foreground(theme.Muted).Render(preview)
+	}
+
+	sections[date] = style.Render("Date") + "\n" + e.inputs[date].View() + "\n" + preview
+	sections[description] = style.Render("Description") + "\n" + e.inputs[description].View() + "\n" +
+		e.suggestionView()
+	sections[amount] = style.Render("Amount") + "\n" + e.inputs[amount].View() + "\n" +
+		e.amountPreview()
+	sections[variation] = style.Render("Varies by") + "\n" + e.inputs[variation].View()
+	sections[category] = style.Render("Category") + "\n" + e.inputs[category].View()
+	sections[tags] = style.Render("Tags") + "\n" + e.inputs[tags].View()
+	sections[repeat] = style.Render("Repeat") + "\n" + e.repeat.View()
+
+	return sections
+}
+
+// handleMouse focuses the clicked field, also choosing the clicked option when it's the repeat
+// field or a template
+func (e *EventView) handleMouse(msg tea.MouseMsg) {
+	if msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress {
+		return
+	}
+
+	if e.choosing {
+		// the templates are listed under their heading
+		e.templates.SelectAt(msg.Y - 1)
+		return
+	}
+
+	top := 0
+	for field, section := range e.sections() {
+		lines := strings.Count(section, "\n") + 1
+		if msg.Y >= top && msg.Y < top+lines {
+			e.focused = FocusedField(field)
+			e.focus()
+
+			// skip past the label
+			if e.focused == repeat {
+				e.repeat.SelectAt(msg.Y - top - 1)
+			}
+			return
+		}
+
+		// sections are separated by a blank line
+		top += lines + 1
+	}
 }
 
 func (e *EventView) View() string {
-	style := lipgloss.NewStyle().Foreground(lipgloss.Color("27"))
+	style := lipgloss.NewStyle().Foreground(theme.Accent)
 
 	var b strings.Builder
-	b.WriteString(style.Render("Date"))
-	b.WriteString("\n")
-	b.WriteString(e.inputs[month].View())
-	b.WriteString("/ ")
-	b.WriteString(e.inputs[day].View())
-	b.WriteString("/ ")
-	b.WriteString(e.inputs[year].View())
-	b.WriteString("\n\n")
-
-	b.WriteString(style.Render("Description"))
-	b.WriteString("\n")
-	b.WriteString(e.inputs[description].View())
-	b.WriteString("\n\n")
-
-	b.WriteString(style.Render("Amount"))
-	b.WriteString("\n")
-	b.WriteString(e.inputs[amount].View())
-	b.WriteString("\n\n")
+	if e.choosing {
+		b.WriteString(style.Render("Templates"))
+		b.WriteString("\n")
+		b.WriteString(e.templates.View())
+		b.WriteString("\n\n")
+		b.WriteString(e.help.View(e.keymap))
+		b.WriteString("\n")
+		return b.String()
+	}
 
-	b.WriteString(style.Render("Repeat"))
-	b.WriteString("\n")
-	b.WriteString(e.repeat.View())
-	b.WriteString("\n\n")
+	for _, section := range e.sections() {
+		b.WriteString(section)
+		b.WriteString("\n\n")
+	}
 
 	b.WriteString(e.help.View(e.keymap))
 	b.WriteString("\n")
@@ -277,9 +402,18 @@ func (e *EventView) View() string {
 }
 
 func (e *EventView) Update(msg tea.Msg) tea.Cmd {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		e.help.Width = size.Width
+	}
+
+	if e.choosing {
+		return e.handleTemplateInput(msg)
+	}
+
 	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		e.help.Width = msg.Width
+	case tea.MouseMsg:
+		e.handleMouse(msg)
+		return nil
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, e.keymap.Help):
@@ -288,21 +422,35 @@ func (e *EventView) Update(msg tea.Msg) tea.Cmd {
 			e.prevInput()
 		case key.Matches(msg, e.keymap.NextField):
 			e.nextInput()
+		case key.Matches(msg, e.keymap.Templates):
+			return e.chooseTemplate()
+		case key.Matches(msg, e.keymap.SaveTemplate):
+			return e.saveTemplate()
+		case key.Matches(msg, e.keymap.Complete) && e.focused == description:
+			// only once the cursor is at the end, before that the key moves it as usual
+			input := &e.inputs[description]
+			atEnd := input.Position() == len([]rune(input.Value()))
+			if suggestion := e.suggestion(); suggestion != nil && atEnd {
+				e.applyTemplate(suggestion)
+				return nil
+			}
 		}
 	}
 
 	e.focus()
 	switch e.focused {
-	case month:
-		e.inputs[month], _ = e.inputs[month].Update(msg)
-	case day:
-		e.inputs[day], _ = e.inputs[day].Update(msg)
-	case year:
-		e.inputs[year], _ = e.inputs[year].Update(msg)
+	case date:
+		e.inputs[date], _ = e.inputs[date].Update(msg)
 	case description:
 		e.inputs[description], _ = e.inputs[description].Update(msg)
 	case amount:
 		e.inputs[amount], _ = e.inputs[amount].Update(msg)
+	case variation:
+		e.inputs[variation], _ = e.inputs[variation].Update(msg)
+	case category:
+		e.inputs[category], _ = e.inputs[category].Update(msg)
+	case tags:
+		e.inputs[tags], _ = e.inputs[tags].Update(msg)
 	case repeat:
 		e.repeat, _ = e.repeat.Update(msg)
 	}
@@ -317,16 +465,18 @@ func (e *EventView) focus() {
 	}
 
 	switch e.focused {
-	case month:
-		e.inputs[month].Focus()
-	case day:
-		e.inputs[day].Focus()
-	case year:
-		e.inputs[year].Focus()
+	case date:
+		e.inputs[date].Focus()
 	case description:
 		e.inputs[description].Focus()
 	case amount:
 		e.inputs[amount].Focus()
+	case variation:
+		e.inputs[variation].Focus()
+	case category:
+		e.inputs[category].Focus()
+	case tags:
+		e.inputs[tags].Focus()
 	case repeat:
 		e.repeat.Focus()
 	}
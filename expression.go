@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// evaluate works out an arithmetic expression such as -1200/3 or 2*45.50+12 so that amounts can be
+// entered without doing the math elsewhere first. Numbers may have thousands separators and a
+// dollar sign, as in $1,234.56, and multiplication and division bind tighter than addition and
+// subtraction.
+func evaluate(str string) (float64, error) {
+	p := expressionParser{input: []rune(str)}
+	if p.skipSpaces(); p.done() {
+		return 0, fmt.Errorf("enter an amount")
+	}
+
+	result, err := p.sum()
+	if err != nil {
+		return 0, err
+	}
+
+	if !p.done() {
+		if p.peek() == ')' {
+			return 0, fmt.Errorf("unmatched ) at position %d", p.pos+1)
+		}
+		return 0, fmt.Errorf("expected an operator at position %d, found %q", p.pos+1, p.peek())
+	}
+
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		return 0, fmt.Errorf("amount is too large")
+	}
+
+	return result, nil
+}
+
+type expressionParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *expressionParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *expressionParser) peek() rune {
+	if p.done() {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *expressionParser) skipSpaces() {
+	for !p.done() && p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+// sum parses terms separated by + and -
+func (p *expressionParser) sum() (float64, error) {
+	result, err := p.product()
+	if err != nil {
+		return 0, err
+	}
+
+	for p.skipSpaces(); p.peek() == '+' || p.peek() == '-'; p.skipSpaces() {
+		operator := p.peek()
+		p.pos++
+
+		value, err := p.product()
+		if err != nil {
+			return 0, err
+		}
+
+		if operator == '+' {
+			result += value
+		} else {
+			result -= value
+		}
+	}
+
+	return result, nil
+}
+
+// product parses factors separated by * and /
+func (p *expressionParser) product() (float64, error) {
+	result, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+
+	for p.skipSpaces(); p.peek() == '*' || p.peek() == '/'; p.skipSpaces() {
+		operator := p.peek()
+		position := p.pos
+		p.pos++
+
+		value, err := p.factor()
+		if err != nil {
+			return 0, err
+		}
+
+		if operator == '*' {
+			result *= value
+		} else if value == 0 {
+			return 0, fmt.Errorf("division by zero at position %d", position+1)
+		} else {
+			result /= value
+		}
+	}
+
+	return result, nil
+}
+
+// factor parses a number, a parenthesized expression or either of them negated
+func (p *expressionParser) factor() (float64, error) {
+	p.skipSpaces()
+
+	switch {
+	case p.done():
+		return 0, fmt.Errorf("expression ends early, expected a number")
+	case p.peek() == '-' || p.peek() == '+':
+		sign := 1.0
+		if p.peek() == '-' {
+			sign = -1
+		}
+		p.pos++
+
+		value, err := p.factor()
+		return sign * value, err
+	case p.peek() == '(':
+		open := p.pos
+		p.pos++
+
+		value, err := p.sum()
+		if err != nil {
+			return 0, err
+		}
+
+		if p.skipSpaces(); p.peek() != ')' {
+			return 0, fmt.Errorf("missing ) for the ( at position %d", open+1)
+		}
+		p.pos++
+
+		return value, nil
+	}
+
+	return p.number()
+}
+
+func (p *expressionParser) number() (float64, error) {
+	start := p.pos
+	if p.peek() == '$' {
+		p.pos++
+	}
+
+	digits := p.pos
+	for !p.done() && (p.peek() >= '0' && p.peek() <= '9' || p.peek() == '.' || p.peek() == ',') {
+		p.pos++
+	}
+
+	number := string(p.input[digits:p.pos])
+	if number == "" {
+		if p.done() {
+			return 0, fmt.Errorf("expression ends early, expected a number")
+		}
+		return 0, fmt.Errorf("expected a number at position %d, found %q", p.pos+1, p.peek())
+	}
+
+	if !validThousands(number) {
+		return 0, fmt.Errorf("misplaced comma in %q at position %d, use commas only between thousands "+
+			"as in 1,234.56", string(p.input[start:p.pos]), start+1)
+	}
+
+	value, err := strconv.ParseFloat(strings.ReplaceAll(number, ",", ""), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q at position %d isn't a number", string(p.input[start:p.pos]), start+1)
+	}
+
+	return value, nil
+}
+
+// validThousands returns whether any commas in the number separate its whole part into groups of
+// three digits, so that a comma typed in place of a decimal point isn't silently dropped
+func validThousands(number string) bool {
+	whole, fraction, _ := strings.Cut(number, ".")
+	if strings.Contains(fraction, ",") {
+		return false
+	}
+	if !strings.Contains(whole, ",") {
+		return true
+	}
+
+	groups := strings.Split(whole, ",")
+	if len(groups[0]) < 1 || len(groups[0]) > 3 {
+		return false
+	}
+
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return false
+		}
+	}
+
+	return true
+}
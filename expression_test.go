@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"42", 42},
+		{"12.5", 12.5},
+		{"-1200/3", -400},
+		{"2*45.50+12", 103},
+		{"2 * (45.50 + 12)", 115},
+		{"10-4-3", 3},
+		{"12/4/3", 1},
+		{"-(3+2)", -5},
+		{"--5", 5},
+		{"+7", 7},
+		{"$1,234.56", 1234.56},
+		{"1,234,567", 1234567},
+		{"-$20 * 3", -60},
+		{".5", 0.5},
+	}
+
+	for _, test := range tests {
+		got, err := evaluate(test.input)
+		if err != nil {
+			t.Errorf("evaluate(%q): %v", test.input, err)
+		} else if got != test.want {
+			t.Errorf("evaluate(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	commas := "use commas only between thousands as in 1,234.56"
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "enter an amount"},
+		{"   ", "enter an amount"},
+		{"1/0", "division by zero at position 2"},
+		{"5/(2-2)", "division by zero at position 2"},
+		{"(1+2", "missing ) for the ( at position 1"},
+		{"1+2)", "unmatched ) at position 4"},
+		{"1+", "expression ends early, expected a number"},
+		{"1 2", "expected an operator at position 3, found '2'"},
+		{"abc", "expected a number at position 1, found 'a'"},
+		{"1.2.3", `"1.2.3" at position 1 isn't a number`},
+		{"1,5", `misplaced comma in "1,5" at position 1, ` + commas},
+		{"1,2,3", `misplaced comma in "1,2,3" at position 1, ` + commas},
+		{"2*12,34", `misplaced comma in "12,34" at position 3, ` + commas},
+		{"1,234,56", `misplaced comma in "1,234,56" at position 1, ` + commas},
+		{"1.5,0", `misplaced comma in "1.5,0" at position 1, ` + commas},
+	}
+
+	for _, test := range tests {
+		_, err := evaluate(test.input)
+		if err == nil {
+			t.Errorf("evaluate(%q): expected an error", test.input)
+		} else if err.Error() != test.want {
+			t.Errorf("evaluate(%q) error = %q, want %q", test.input, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TransactionFilter limits the forecast to transactions of a kind, amount range, date range or
+// frequency. The zero value matches every transaction.
+type TransactionFilter struct {
+	income  bool
+	expense bool
+
+	// amounts are compared by magnitude, so 50..200 matches both income and expenses of that size
+	min *float32
+	max *float32
+
+	from time.Time
+	to   time.Time
+
+	frequencies []Frequency
+}
+
+const filterHelp = "income, expense, amount:50..200, from:2024-01-01, to:2024-12-31, freq:monthly,weekly"
+
+// parseFilter parses space separated filter terms such as "expense amount:100.. freq:monthly"
+func parseFilter(str string) (TransactionFilter, error) {
+	var filter TransactionFilter
+
+	for _, term := range strings.Fields(str) {
+		name, value, _ := strings.Cut(strings.ToLower(term), ":")
+
+		switch name {
+		case "income":
+			filter.income = true
+		case "expense":
+			filter.expense = true
+		case "amount":
+			low, high, found := strings.Cut(value, "..")
+			if !found {
+				high = low
+			}
+
+			var err error
+			if filter.min, err = parseFilterAmount(low); err != nil {
+				return filter, err
+			}
+			if filter.max, err = parseFilterAmount(high); err != nil {
+				return filter, err
+			}
+		case "from", "to":
+			date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+			if err != nil {
+				return filter, fmt.Errorf("%s date must look like 2024-01-31", name)
+			}
+
+			if name == "from" {
+				filter.from = date
+			} else {
+				filter.to = date
+			}
+		case "freq":
+			for _, value := range strings.Split(value, ",") {
+				frequency, err := parseFrequency(value)
+				if err != nil {
+					return filter, err
+				}
+
+				filter.frequencies = append(filter.frequencies, frequency)
+			}
+		default:
+			return filter, fmt.Errorf("unknown filter %q", term)
+		}
+	}
+
+	return filter, nil
+}
+
+func parseFilterAmount(str string) (*float32, error) {
+	if str == "" {
+		return nil, nil
+	}
+
+	amount, err := strconv.ParseFloat(str, 32)
+	if err != nil {
+		return nil, fmt.Errorf("amount %q is invalid", str)
+	}
+
+	result := float32(amount)
+	if result < 0 {
+		result = -result
+	}
+
+	return &result, nil
+}
+
+func parseFrequency(str string) (Frequency, error) {
+	for f := Once; f <= Yearly; f++ {
+		if strings.EqualFold(f.toString(), str) {
+			return f, nil
+		}
+	}
+
+	return Once, fmt.Errorf("unknown frequency %q", str)
+}
+
+func (f *TransactionFilter) active() bool {
+	return f.income || f.expense || f.min != nil || f.max != nil || !f.from.IsZero() ||
+		!f.to.IsZero() || len(f.frequencies) > 0
+}
+
+func (f *TransactionFilter) matches(tx *Transaction) bool {
+	amount := tx.event.Amount
+
+	// asking for both income and expenses is the same as asking for neither
+	if f.income != f.expense {
+		if f.income && amount <= 0 {
+			return false
+		}
+		if f.expense && amount >= 0 {
+			return false
+		}
+	}
+
+	if amount < 0 {
+		amount = -amount
+	}
+	if f.min != nil && amount < *f.min {
+		return false
+	}
+	if f.max != nil && amount > *f.max {
+		return false
+	}
+
+	if !f.from.IsZero() && tx.date.Before(f.from) {
+		return false
+	}
+	if !f.to.IsZero() && tx.date.After(f.to) {
+		return false
+	}
+
+	if len(f.frequencies) > 0 {
+		found := false
+		for _, frequency := range f.frequencies {
+			if tx.event.Frequency == frequency {
+				found = true
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
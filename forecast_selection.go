@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectionKey identifies a transaction across changes to the account by its event and date
+type selectionKey struct {
+	hash uint64
+	date int64
+}
+
+func keyOf(tx *Transaction) selectionKey {
+	return selectionKey{hash: tx.hash, date: tx.date.Unix()}
+}
+
+// isSelected returns whether the transaction on the given row is selected
+func (f *ForecastView) isSelected(row int) bool {
+	if row < 0 || row >= len(f.transactions) {
+		return false
+	}
+
+	if f.selected[keyOf(&f.transactions[row])] {
+		return true
+	}
+
+	if f.anchor < 0 {
+		return false
+	}
+
+	cursor := f.table.Cursor()
+	return (row >= f.anchor && row <= cursor) || (row <= f.anchor && row >= cursor)
+}
+
+func (f *ForecastView) hasSelection() bool {
+	return len(f.selected) > 0 || f.anchor >= 0
+}
+
+func (f *ForecastView) toggleSelected(row int) {
+	if row < 0 || row >= len(f.transactions) {
+		return
+	}
+
+	k := keyOf(&f.transactions[row])
+	if f.selected[k] {
+		delete(f.selected, k)
+	} else {
+		f.selected[k] = true
+	}
+}
+
+// selectRange adds the rows between the anchor and the cursor to the selection
+func (f *ForecastView) selectRange() {
+	for row := range f.transactions {
+		if f.isSelected(row) {
+			f.selected[keyOf(&f.transactions[row])] = true
+		}
+	}
+
+	f.anchor = -1
+}
+
+// clearSelection empties the selection in place, since actions run after a confirmation clear it
+// through an older copy of the view
+func (f *ForecastView) clearSelection() {
+	for k := range f.selected {
+		delete(f.selected, k)
+	}
+	f.anchor = -1
+}
+
+// selectedTransactions returns the selected transactions in date order
+func (f *ForecastView) selectedTransactions() []Transaction {
+	var result []Transaction
+	for row := range f.transactions {
+		if f.isSelected(row) {
+			result = append(result, f.transactions[row])
+		}
+	}
+
+	return result
+}
+
+// selectionSummary describes the selection for the status line
+func (f *ForecastView) selectionSummary() string {
+	if !f.hasSelection() {
+		return ""
+	}
+
+	transactions := f.selectedTransactions()
+
+	var total float32
+	for _, tx := range transactions {
+		total += tx.event.Amount
+	}
+
+	return fmt.Sprintf("%d selected, total %s", len(transactions),
+		f.account.currency.FormatMoney(total))
+}
+
+// remember saves the state of the account so the next change can be undone
+func (f *ForecastView) remember() {
+	f.undo.remember(f.account)
+}
+
+func (f *ForecastView) undoLast() tea.Cmd {
+	if !f.undo.undo(f.account) {
+		return status("Nothing to undo")
+	}
+
+	f.clearSelection()
+	return status("Undone")
+}
+
+// applyToSelection calls action on each of the transactions that can still be found in the account,
+// returning how many it acted on. Each is looked up again after the previous one was acted on since
+// the action may move events around.
+func (f *ForecastView) applyToSelection(transactions []Transaction,
+	action func(tx *Transaction) bool) int {
+	count := 0
+	for _, selected := range transactions {
+		tx := f.account.findTransaction(selected.hash, selected.date)
+		if tx != nil && action(tx) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// handleSelectionInput applies an action to every selected transaction as one undoable step
+func (f *ForecastView) handleSelectionInput(msg tea.KeyMsg) tea.Cmd {
+	transactions := f.selectedTransactions()
+	f.selectRange()
+
+	var total float32
+	for _, tx := range transactions {
+		total += tx.event.Amount
+	}
+
+	// later occurrences of a repeating event can't be completed until the earlier ones are, but
+	// completing them in date order takes care of that when they are all selected
+	complete := func(update_balance bool) func(tx *Transaction) bool {
+		return func(tx *Transaction) bool {
+			// an envelope's allowance can only be spent, it's removed along with the envelope
+			if tx.envelope && !update_balance {
+				return false
+			}
+
+			if !tx.envelope && tx.repeats() && !tx.isFirstOccurrence() {
+				return false
+			}
+
+			f.account.txComplete(tx, update_balance)
+			return true
+		}
+	}
+
+	switch {
+	case key.Matches(msg, f.keymap.Done):
+		prompt := fmt.Sprintf("Mark %d transactions done and change the balance by %s?",
+			len(transactions), f.account.currency.FormatMoney(total))
+		return f.confirmation.ask(prompt, func() tea.Cmd {
+			f.remember()
+			count := f.applyToSelection(transactions, complete(true))
+			f.clearSelection()
+			return status("Marked %d of %d transactions done", count, len(transactions))
+		})
+	case key.Matches(msg, f.keymap.Delete):
+		prompt := fmt.Sprintf("Delete %d transactions?", len(transactions))
+		return f.confirmation.ask(prompt, func() tea.Cmd {
+			f.remember()
+			count := f.applyToSelection(transactions, complete(false))
+			f.clearSelection()
+			return status("Deleted %d of %d transactions", count, len(transactions))
+		})
+	case key.Matches(msg, f.keymap.SetToday):
+		f.remember()
+		count := f.applyToSelection(transactions, func(tx *Transaction) bool {
+			if tx.envelope || (tx.repeats() && !tx.isFirstOccurrence()) {
+				return false
+			}
+
+			f.account.txSetToToday(tx)
+			return true
+		})
+		f.clearSelection()
+		return status("Moved %d of %d transactions to today", count, len(transactions))
+	case key.Matches(msg, f.keymap.DatePrevious), key.Matches(msg, f.keymap.DateNext):
+		days := 1
+		if key.Matches(msg, f.keymap.DatePrevious) {
+			days = -1
+		}
+
+		// moving an occurrence moves its whole event, so each event is only moved once and the
+		// selection follows its occurrences
+		moved := map[uint64]bool{}
+		f.remember()
+		f.applyToSelection(transactions, func(tx *Transaction) bool {
+			if tx.envelope || moved[tx.hash] {
+				return false
+			}
+
+			moved[tx.hash] = true
+			if days < 0 {
+				f.account.txDatePrevious(tx)
+			} else {
+				f.account.txDateNext(tx)
+			}
+			return true
+		})
+
+		selected := map[selectionKey]bool{}
+		for _, tx := range transactions {
+			date := tx.date
+			if moved[tx.hash] {
+				date = date.AddDate(0, 0, days)
+			}
+
+			selected[selectionKey{hash: tx.hash, date: date.Unix()}] = true
+		}
+
+		f.selected = selected
+		f.anchor = -1
+		return nil
+	}
+
+	return nil
+}
@@ -2,11 +2,8 @@ package main
 
 import (
 	"fmt"
-	"log"
-	"os"
-	"strconv"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -14,34 +11,56 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"golang.org/x/term"
 )
 
 type ForecastViewKeyMap struct {
-	DatePrevious key.Binding
-	DateNext     key.Binding
-	Delete       key.Binding
-	Done         key.Binding
-	SetToday     key.Binding
+	DatePrevious key.Binding `mode:"table"`
+	DateNext     key.Binding `mode:"table"`
+	Delete       key.Binding `mode:"table"`
+	Done         key.Binding `mode:"table"`
+	SetToday     key.Binding `mode:"table"`
 	Reload       key.Binding
 	EditEvent    key.Binding
+	EditOnward   key.Binding
+	Duplicate    key.Binding `mode:"table"`
+	Pause        key.Binding `mode:"table"`
 	AddEvent     key.Binding
 
+	FilterCategory  key.Binding
+	CategorySummary key.Binding
+	Envelopes       key.Binding
+	Goals           key.Binding
+	Scenarios       key.Binding
+	Simulate        key.Binding
+	Chart           key.Binding
+	Calendar        key.Binding
+	Events          key.Binding
+	Search          key.Binding `mode:"table"`
+	NextMatch       key.Binding `mode:"table"`
+	PreviousMatch   key.Binding `mode:"table"`
+	Filter          key.Binding
+	Select          key.Binding `mode:"table"`
+	SelectRange     key.Binding `mode:"table"`
+	Undo            key.Binding `mode:"table"`
+
 	FocusTable  key.Binding
-	EditBalance key.Binding
+	EditBalance key.Binding `mode:"table"`
 	Help        key.Binding
 	Confirm     key.Binding
 	Save        key.Binding
 	Quit        key.Binding
 
-	LineUp     key.Binding
-	LineDown   key.Binding
+	LineUp     key.Binding `mode:"table"`
+	LineDown   key.Binding `mode:"table"`
 	GotoTop    key.Binding
 	GotoBottom key.Binding
+
+	ChartLeft  key.Binding `mode:"chart"`
+	ChartRight key.Binding `mode:"chart"`
 }
 
 func NewForecastViewKeyMap() ForecastViewKeyMap {
-	return ForecastViewKeyMap{
+	k := ForecastViewKeyMap{
 		DatePrevious: key.NewBinding(
 			key.WithKeys("h"),
 			key.WithHelp("h", "date to previous day"),
@@ -70,11 +89,88 @@ func NewForecastViewKeyMap() ForecastViewKeyMap {
 			key.WithKeys("e"),
 			key.WithHelp("e", "edit event"),
 		),
+		EditOnward: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "change series from here on"),
+		),
+		Duplicate: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "duplicate event"),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "pause/resume event"),
+		),
 		AddEvent: key.NewBinding(
 			key.WithKeys("a"),
 			key.WithHelp("a", "add event"),
 		),
 
+		FilterCategory: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "filter by category"),
+		),
+		CategorySummary: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "category summary"),
+		),
+		Envelopes: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "budget envelopes"),
+		),
+		Goals: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "savings goals"),
+		),
+		Scenarios: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "what-if scenarios"),
+		),
+		Simulate: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "toggle simulated balance range"),
+		),
+		Chart: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "toggle balance chart"),
+		),
+		Calendar: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "calendar"),
+		),
+		Events: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "list events"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search descriptions"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PreviousMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous match"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "filter by amount, date or frequency"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select"),
+		),
+		SelectRange: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "select range"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
+
 		FocusTable: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "focus table"),
@@ -116,7 +212,19 @@ func NewForecastViewKeyMap() ForecastViewKeyMap {
 			key.WithKeys("end", "G"),
 			key.WithHelp("G/end", "go to end"),
 		),
+
+		ChartLeft: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "earlier"),
+		),
+		ChartRight: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "later"),
+		),
 	}
+
+	settings.bind("forecast", &k)
+	return k
 }
 
 func (k ForecastViewKeyMap) ShortHelp() []key.Binding {
@@ -126,8 +234,13 @@ func (k ForecastViewKeyMap) ShortHelp() []key.Binding {
 func (k ForecastViewKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.LineUp, k.LineDown, k.GotoTop, k.GotoBottom},
-		{k.DatePrevious, k.DateNext, k.SetToday, k.Done, k.Delete, k.EditEvent},
+		{k.DatePrevious, k.DateNext, k.SetToday, k.Done, k.Delete, k.EditEvent, k.EditOnward,
+			k.Duplicate, k.Pause},
 		{k.AddEvent, k.EditBalance, k.FocusTable},
+		{k.Search, k.NextMatch, k.PreviousMatch, k.Filter},
+		{k.Select, k.SelectRange, k.Undo},
+		{k.FilterCategory, k.CategorySummary, k.Envelopes, k.Goals, k.Scenarios, k.Simulate, k.Chart,
+			k.Calendar, k.Events},
 		{k.Reload, k.Save, k.Quit},
 	}
 }
@@ -141,21 +254,91 @@ type ForecastView struct {
 
 	account      *Account
 	transactions []Transaction
+	summary      BalanceSummary
+
+	// only transactions of this category are shown when set
+	category string
+
+	// rows whose description contains the query can be jumped between. While typing, matches are
+	// looked for from the row the search started on.
+	search       textinput.Model
+	query        string
+	searchOrigin int
+
+	// only transactions matching the filter are shown
+	filterInput textinput.Model
+	filter      TransactionFilter
+	filterError string
+
+	// whether amounts which vary are simulated to show the range the balance may end up in
+	simulation bool
+	simulated  SimulationResult
+
+	// fingerprint of what was simulated, the simulation is only run again once it changes
+	simulatedInput uint64
+
+	confirmation Confirmation
+	pause        PausePrompt
+
+	// transactions selected to act on together. While selecting a range, the rows between the anchor
+	// and the cursor are selected as well.
+	selected map[selectionKey]bool
+	anchor   int
+
+	undo *UndoHistory
+
+	// the chart replaces the table when shown
+	chart     Chart
+	showChart bool
+
+	width  int
+	height int
+
+	// visible[i] is the index of the column spec shown in column i
+	columns []table.Column
+	visible []int
+}
+
+// forecastColumns returns the table columns, which include the baseline balance and the difference
+// to it when showing a scenario and the simulated balance percentiles when simulating. The extra
+// columns are dropped on narrow terminals.
+func forecastColumns(scenario bool, simulation bool) []ColumnSpec {
+	columns := []ColumnSpec{
+		{Title: "", Min: 1, Preferred: 1},
+		{Title: "Date", Min: 18, Preferred: 20},
+		{Title: "Description", Min: 11, Preferred: 40, Max: 80, Fill: true},
+		{Title: "Income", Min: 11, Preferred: 15},
+		{Title: "Expense", Min: 11, Preferred: 15},
+		// negative balances are styled, which takes up room in the cell
+		{Title: "Balance", Min: 16, Preferred: 20},
+	}
+
+	if scenario {
+		columns = append(columns,
+			ColumnSpec{Title: "Baseline", Min: 16, Preferred: 20, Optional: true},
+			ColumnSpec{Title: "Difference", Min: 12, Preferred: 15, Optional: true},
+		)
+	}
+
+	if simulation {
+		columns = append(columns,
+			ColumnSpec{Title: "P10", Min: 16, Preferred: 16, Optional: true},
+			ColumnSpec{Title: "P50", Min: 16, Preferred: 16, Optional: true},
+			ColumnSpec{Title: "P90", Min: 16, Preferred: 16, Optional: true},
+		)
+	}
+
+	return columns
 }
 
+// terminal size assumed until the first window size message arrives
 const (
-	selectedForeground = lipgloss.Color("229")
-	selectedBackground = lipgloss.Color("27")
+	defaultWidth  = 120
+	defaultHeight = 40
 )
 
-func NewForecastView(account *Account) ForecastView {
-	columns := []table.Column{
-		{Title: "Date", Width: 20},
-		{Title: "Description", Width: 40},
-		{Title: "Income", Width: 15},
-		{Title: "Expense", Width: 15},
-		{Title: "Balance", Width: 20},
-	}
+func NewForecastView(account *Account, undo *UndoHistory) ForecastView {
+	columns, visible := layoutColumns(forecastColumns(account.isSandbox(), false), defaultWidth)
 
 	style := table.DefaultStyles()
 	style.Header = style.Header.
@@ -164,8 +347,8 @@ func NewForecastView(account *Account) ForecastView {
 		BorderBottom(true)
 
 	style.Selected = style.Selected.
-		Foreground(selectedForeground).
-		Background(selectedBackground).
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground).
 		Bold(false)
 
 	t := table.New(
@@ -179,16 +362,47 @@ func NewForecastView(account *Account) ForecastView {
 	t.KeyMap.HalfPageUp = key.NewBinding(key.WithDisabled())
 	t.KeyMap.HalfPageDown = key.NewBinding(key.WithDisabled())
 
+	keymap := NewForecastViewKeyMap()
+	t.KeyMap.LineUp = keymap.LineUp
+	t.KeyMap.LineDown = keymap.LineDown
+	t.KeyMap.GotoTop = keymap.GotoTop
+	t.KeyMap.GotoBottom = keymap.GotoBottom
+
 	b := textinput.New()
 	b.Prompt = "Current balance: "
+	b.Validate = validateExpression
+
+	search := textinput.New()
+	search.Prompt = "/"
+
+	filter := textinput.New()
+	filter.Prompt = "Filter: "
+	filter.Placeholder = filterHelp
 
 	f := ForecastView{
-		keymap: NewForecastViewKeyMap(),
+		keymap: keymap,
 		help:   help.New(),
 
 		table:   t,
 		balance: b,
 
+		search:      search,
+		filterInput: filter,
+
+		confirmation: NewConfirmation(),
+		pause:        NewPausePrompt(),
+
+		selected: map[selectionKey]bool{},
+		anchor:   -1,
+		undo:     undo,
+
+		chart: NewChart(),
+
+		width:   defaultWidth,
+		height:  defaultHeight,
+		columns: columns,
+		visible: visible,
+
 		account:      account,
 		transactions: nil,
 	}
@@ -197,14 +411,147 @@ func NewForecastView(account *Account) ForecastView {
 	return f
 }
 
+// setAccount switches the forecast to another account, either the real one or a scenario's sandbox
+func (f *ForecastView) setAccount(account *Account) {
+	if f.account == account {
+		return
+	}
+
+	f.account = account
+	f.category = ""
+	f.clearSelection()
+	f.undo.forget()
+	f.updateColumns()
+	f.table.SetCursor(0)
+	f.regenerateRows()
+}
+
+// reload replaces the account with what was last saved. What was selected and what could be undone
+// belonged to the account as it was, so both are forgotten.
+func (f *ForecastView) reload() tea.Cmd {
+	f.clearSelection()
+	f.undo.forget()
+	return reloadAccount(f.account)
+}
+
+func (f *ForecastView) updateColumns() {
+	columns, visible := layoutColumns(forecastColumns(f.account.isSandbox(), f.simulation), f.width)
+
+	// the rows must be cleared first since the table renders them against the new columns
+	f.table.SetRows(nil)
+	f.table.SetColumns(columns)
+	f.columns = columns
+	f.visible = visible
+}
+
+// tableHeight returns how many rows fit on screen alongside the header, inputs and help
+func (f *ForecastView) tableHeight(rows int) int {
+	// the summary, the line naming what's shown and a blank line above the table, and a blank line
+	// below it
+	lines := lipgloss.Height(f.summaryView()) + 2 + tableHeaderLines + 1
+
+	// each input shown below the table is followed by a blank line
+	if f.search.Focused() {
+		lines += lipgloss.Height(f.search.View()) + 1
+	}
+	if f.confirmation.active() {
+		lines += lipgloss.Height(f.confirmation.View()) + 1
+	}
+	if f.pause.active() {
+		lines += lipgloss.Height(f.pause.View()) + 1
+	}
+	if f.filterInput.Focused() {
+		// the filter's error, if any, is on the line under it
+		lines += lipgloss.Height(f.filterInput.View()) + 1 + 1
+	}
+	lines += lipgloss.Height(f.help.View(f.keymap))
+
+	return fitRows(rows, f.height, lines)
+}
+
+func (f *ForecastView) toggleSimulation() {
+	f.simulation = !f.simulation
+	cursor := f.table.Cursor()
+	f.updateColumns()
+	f.regenerateRows()
+	f.table.SetCursor(cursor)
+}
+
+func (f *ForecastView) formatBalance(balance float32) string {
+	balance_str := f.account.currency.FormatMoney(balance)
+	if balance < 0 {
+		balance_str = theme.negative(balance_str)
+	}
+
+	return balance_str
+}
+
 func (f *ForecastView) regenerateRows() {
-	until := time.Now().AddDate(0, 4, 0)
-	f.transactions = f.account.predict(until)
+	transactions := f.account.predict(forecastHorizon())
+	f.summary = f.account.summarize(transactions)
+	f.transactions = make([]Transaction, 0, len(transactions))
 
 	balance := f.account.Balance
 
-	rows := make([]table.Row, 0, len(f.transactions))
-	for _, transaction := range f.transactions {
+	// when showing a scenario, the real account's balance is walked alongside it so the two can be
+	// compared on every date
+	var baseline []Transaction
+	var baseline_balance float32
+	if f.account.isSandbox() {
+		baseline = f.account.parent.predict(forecastHorizon())
+		baseline_balance = f.account.parent.Balance
+	}
+
+	if f.simulation {
+		if input := f.account.simulationInput(transactions); input != f.simulatedInput {
+			f.simulated = f.account.simulate(transactions, simulationRuns, simulationSeed)
+			f.simulatedInput = input
+		}
+	}
+
+	if f.showChart {
+		f.chart.setPoints(dailyBalances(f.account.Balance, transactions, forecastHorizon()),
+			f.account.BalanceFloor)
+	}
+
+	// occurrences skipped while their event is paused are shown dimmed without changing the balance,
+	// after any others on the same day
+	entries := append(append([]Transaction(nil), transactions...),
+		f.account.pausedTransactions(forecastHorizon())...)
+	sort.SliceStable(entries, func(a int, b int) bool {
+		return entries[a].date.Before(entries[b].date)
+	})
+
+	rows := make([]table.Row, 0, len(entries))
+	next := 0
+	for _, transaction := range entries {
+		// i is the index of the transaction among those that do happen, for the simulated balances
+		i := next
+		if !transaction.paused {
+			// hidden transactions still count towards the running balance
+			balance += transaction.event.Amount
+			next++
+		}
+
+		if f.category != "" && transaction.event.category() != f.category {
+			continue
+		}
+		if !f.filter.matches(&transaction) {
+			continue
+		}
+
+		f.transactions = append(f.transactions, transaction)
+
+		var marker string
+		if f.isSelected(len(f.transactions) - 1) {
+			marker = "•"
+		}
+
+		if transaction.paused {
+			rows = append(rows, f.pausedRow(marker, &transaction))
+			continue
+		}
+
 		var income string
 		var expense string
 
@@ -214,33 +561,95 @@ func (f *ForecastView) regenerateRows() {
 			expense = f.account.currency.FormatMoney(transaction.event.Amount * -1)
 		}
 
-		balance += transaction.event.Amount
-		balance_str := f.account.currency.FormatMoney(balance)
-		if balance < 0 {
-			balance_str = fmt.Sprintf(("\x1b[31m%s\x1b[0m"), balance_str)
-		}
-
-		rows = append(rows, table.Row{
+		row := table.Row{
+			marker,
 			transaction.date.Format("January 2, 2006"),
 			transaction.event.Description,
 			income,
 			expense,
-			balance_str,
-		})
+			f.formatBalance(balance),
+		}
+
+		if f.account.isSandbox() {
+			for len(baseline) > 0 && !baseline[0].date.After(transaction.date) {
+				baseline_balance += baseline[0].event.Amount
+				baseline = baseline[1:]
+			}
+
+			row = append(row,
+				f.formatBalance(baseline_balance),
+				f.account.currency.FormatMoney(balance-baseline_balance),
+			)
+		}
+
+		if f.simulation {
+			row = append(row,
+				f.formatBalance(f.simulated.P10[i]),
+				f.formatBalance(f.simulated.P50[i]),
+				f.formatBalance(f.simulated.P90[i]),
+			)
+		}
+
+		rows = append(rows, selectColumns(row, f.visible))
 	}
 
-	_, term_height, err := term.GetSize(int(os.Stdout.Fd()))
-	if err != nil {
-		log.Fatal(err)
+	f.table.SetHeight(f.tableHeight(len(rows)))
+	f.table.SetRows(rows)
+
+	// the cursor is left past the end when the last rows are removed
+	f.table.SetCursor(f.table.Cursor())
+}
+
+// pausedRow shows an occurrence skipped while its event is paused, which has no balance of its own
+func (f *ForecastView) pausedRow(marker string, transaction *Transaction) table.Row {
+	row := make(table.Row, 0, len(f.columns))
+	for _, index := range f.visible {
+		var cell string
+		switch index {
+		case 0:
+			row = append(row, marker)
+			continue
+		case 1:
+			// shorter than the other dates to leave room for dimming it
+			cell = transaction.date.Format("Jan 2, 2006")
+		case 2:
+			cell = transaction.event.Description + " (paused)"
+		case 3:
+			if transaction.event.Amount > 0 {
+				cell = f.account.currency.FormatMoney(transaction.event.Amount)
+			}
+		case 4:
+			if transaction.event.Amount <= 0 {
+				cell = f.account.currency.FormatMoney(transaction.event.Amount * -1)
+			}
+		}
+
+		row = append(row, faint(cell, f.columns[len(row)].Width))
 	}
 
-	height := len(f.transactions)
-	if height > term_height {
-		height = term_height
+	return row
+}
+
+// summaryView shows the balance, which is edited in place, along with the minimum balance and what
+// is safe to spend
+func (f *ForecastView) summaryView() string {
+	balance := f.balance.View()
+	if f.balance.Focused() {
+		if result, err := evaluate(f.balance.Value()); err != nil {
+			balance += lipgloss.NewStyle().Foreground(theme.Negative).Render("  " + err.Error())
+		} else {
+			balance += lipgloss.NewStyle().Foreground(theme.Muted).Render(
+				"  = " + f.account.currency.FormatMoney(result))
+		}
 	}
 
-	f.table.SetHeight(height)
-	f.table.SetRows(rows)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		balance,
+		fmt.Sprintf("Minimum balance: %s on %s",
+			f.account.currency.FormatMoney(f.summary.MinimumBalance),
+			f.summary.MinimumDate.Format("January 2, 2006")),
+		fmt.Sprintf("Safe to spend: %s", f.account.currency.FormatMoney(f.summary.SafeToSpend)),
+	)
 }
 
 func (f *ForecastView) View() string {
@@ -249,36 +658,119 @@ func (f *ForecastView) View() string {
 		f.balance.Blur() // setting value apparently focuses the textinput
 	}
 
+	// the summary sits at the right hand edge of the table, over the balance, unless the table is
+	// wider than the window
+	width := tableWidth(f.columns)
+	if width > f.width {
+		width = f.width
+	}
+
 	var b strings.Builder
-	b.WriteString(strings.Repeat(" ", 82))
-	b.WriteString(f.balance.View())
+	b.WriteString(lipgloss.PlaceHorizontal(width, lipgloss.Right, f.summaryView()))
+	b.WriteString("\n")
+	if f.account.isSandbox() {
+		b.WriteString(fmt.Sprintf("Scenario: %s  ", f.account.scenarioName()))
+	}
+	if f.category != "" {
+		b.WriteString(fmt.Sprintf("Category: %s  ", f.category))
+	}
+	if f.filter.active() {
+		b.WriteString(fmt.Sprintf("Filter: %s  ", f.filterInput.Value()))
+	}
+	if f.query != "" && !f.search.Focused() {
+		b.WriteString(fmt.Sprintf("Search: %s  ", f.query))
+	}
+	if f.simulation {
+		b.WriteString(fmt.Sprintf("Chance of going negative: %.0f%% (%d simulations)",
+			f.simulated.NegativeProbability*100, simulationRuns))
+	}
 	b.WriteString("\n\n")
-	b.WriteString(f.table.View())
+	if f.showChart {
+		b.WriteString(f.chart.View(f.account.currency))
+	} else {
+		b.WriteString(f.table.View())
+	}
 	b.WriteString("\n\n")
+	if f.search.Focused() {
+		b.WriteString(f.search.View())
+		b.WriteString("\n\n")
+	}
+	if f.confirmation.active() {
+		b.WriteString(f.confirmation.View())
+		b.WriteString("\n\n")
+	}
+	if f.pause.active() {
+		b.WriteString(f.pause.View())
+		b.WriteString("\n\n")
+	}
+	if f.filterInput.Focused() {
+		b.WriteString(f.filterInput.View())
+		b.WriteString("\n")
+		b.WriteString(f.filterError)
+		b.WriteString("\n\n")
+	}
 	b.WriteString(f.help.View(f.keymap))
 	b.WriteString("\n")
 	return b.String()
 }
 
 func (f *ForecastView) Update(msg tea.Msg) tea.Cmd {
+	if handled, cmd := f.confirmation.Update(msg); handled {
+		f.regenerateRows()
+		return cmd
+	}
+	if handled, cmd := f.pause.Update(msg); handled {
+		f.regenerateRows()
+		return cmd
+	}
+
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		f.help.Width = msg.Width
+		f.width = msg.Width
+		f.height = msg.Height
+
+		// the inputs, and the filter's long placeholder, stop at the edge of the window leaving room
+		// for the cursor
+		f.search.Width = msg.Width - lipgloss.Width(f.search.Prompt) - 1
+		f.filterInput.Width = msg.Width - lipgloss.Width(f.filterInput.Prompt) - 1
+
+		cursor := f.table.Cursor()
+		f.updateColumns()
+		f.regenerateRows()
+		f.table.SetCursor(cursor)
+
+		// leave room for the header, cursor readout and help
+		f.chart.setSize(msg.Width, msg.Height-10)
 	case tea.KeyMsg:
-		if f.table.Focused() {
+		if f.showChart {
+			cmd = f.handleChartInput(msg)
+		} else if f.table.Focused() {
 			cmd = f.handleTableInput(msg)
 		} else if f.balance.Focused() {
 			cmd = f.handleBalanceInput(msg)
+		} else if f.search.Focused() {
+			cmd = f.handleSearchInput(msg)
+		} else if f.filterInput.Focused() {
+			cmd = f.handleFilterInput(msg)
 		}
 
 		f.regenerateRows()
+	case tea.MouseMsg:
+		cmd = f.handleMouse(msg)
 	}
 
 	return cmd
 }
 
+// browsing returns whether the view is showing the forecast rather than taking text input
+func (f *ForecastView) browsing() bool {
+	return !f.balance.Focused() && !f.search.Focused() && !f.filterInput.Focused() &&
+		!f.confirmation.active() && !f.pause.active()
+}
+
 func (f *ForecastView) setCursorToTransactionWithHash(hash uint64) {
 	index := 0
 	for i, transaction := range f.transactions {
@@ -291,7 +783,54 @@ func (f *ForecastView) setCursorToTransactionWithHash(hash uint64) {
 	f.table.SetCursor(index)
 }
 
+// nextCategory cycles the category filter through every category and then back to showing all
+// transactions
+func (f *ForecastView) nextCategory() {
+	categories := f.account.categories()
+
+	next := ""
+	for i, category := range categories {
+		if f.category == "" {
+			next = category
+			break
+		}
+
+		if category == f.category && i+1 < len(categories) {
+			next = categories[i+1]
+			break
+		}
+	}
+
+	f.category = next
+	f.table.SetCursor(0)
+}
+
 func (f *ForecastView) handleTableInput(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		// these must remain possible when the current filter matches nothing
+		switch {
+		case key.Matches(msg, f.keymap.FilterCategory):
+			f.nextCategory()
+			return nil
+		case key.Matches(msg, f.keymap.Simulate):
+			f.toggleSimulation()
+			return nil
+		case key.Matches(msg, f.keymap.Chart):
+			f.showChart = true
+			return nil
+		case key.Matches(msg, f.keymap.Filter):
+			f.table.Blur()
+			f.filterError = ""
+			f.filterInput.CursorEnd()
+			f.filterInput.Focus()
+			return nil
+		case key.Matches(msg, f.keymap.Reload):
+			return f.reload()
+		case key.Matches(msg, f.keymap.Save):
+			return saveAccount(f.account)
+		}
+	}
+
 	if len(f.transactions) == 0 {
 		return nil
 	}
@@ -303,31 +842,108 @@ func (f *ForecastView) handleTableInput(msg tea.Msg) tea.Cmd {
 		switch {
 		case key.Matches(msg, f.keymap.Help):
 			f.help.ShowAll = !f.help.ShowAll
+		case key.Matches(msg, f.keymap.Select):
+			f.toggleSelected(f.table.Cursor())
+			f.table.MoveDown(1)
+			return nil
+		case key.Matches(msg, f.keymap.SelectRange):
+			if f.anchor < 0 {
+				f.anchor = f.table.Cursor()
+			} else {
+				f.selectRange()
+			}
+			return nil
+		case key.Matches(msg, f.keymap.FocusTable):
+			f.clearSelection()
+			return nil
+		case key.Matches(msg, f.keymap.Undo):
+			return f.undoLast()
+		case f.hasSelection() && (key.Matches(msg, f.keymap.DatePrevious) ||
+			key.Matches(msg, f.keymap.DateNext) || key.Matches(msg, f.keymap.Delete) ||
+			key.Matches(msg, f.keymap.Done) || key.Matches(msg, f.keymap.SetToday)):
+			return f.handleSelectionInput(msg)
+		case tx.paused && (key.Matches(msg, f.keymap.DatePrevious) ||
+			key.Matches(msg, f.keymap.DateNext) || key.Matches(msg, f.keymap.Delete) ||
+			key.Matches(msg, f.keymap.Done) || key.Matches(msg, f.keymap.SetToday)):
+			return status("%s is paused, press %s to resume it", tx.event.Description,
+				f.keymap.Pause.Help().Key)
+		case key.Matches(msg, f.keymap.Pause):
+			if tx.envelope {
+				return status("Envelopes can't be paused")
+			}
+
+			return f.pause.toggle(f.account, f.account.findEventIndex(&tx), f.remember)
 		case key.Matches(msg, f.keymap.DatePrevious):
+			f.remember()
 			hash := tx.hash
 			f.account.txDatePrevious(&tx)
 			f.regenerateRows()
 			f.setCursorToTransactionWithHash(hash)
 		case key.Matches(msg, f.keymap.DateNext):
+			f.remember()
 			hash := tx.hash
 			f.account.txDateNext(&tx)
 			f.regenerateRows()
 			f.setCursorToTransactionWithHash(hash)
 		case key.Matches(msg, f.keymap.Delete):
-			f.account.txComplete(&tx, false)
+			if tx.envelope {
+				return status("Envelopes can't be deleted, press %s to remove one",
+					f.keymap.Envelopes.Help().Key)
+			}
+
+			if tx.repeats() && !tx.isFirstOccurrence() {
+				return status("Only the next %s can be deleted", tx.event.Description)
+			}
+
+			prompt := fmt.Sprintf("Delete %s on %s?", tx.event.Description, tx.date.Format("January 2"))
+			return f.confirmation.ask(prompt, func() tea.Cmd {
+				f.remember()
+				f.account.txComplete(&tx, false)
+				return status("Deleted %s on %s", tx.event.Description, tx.date.Format("January 2"))
+			})
 		case key.Matches(msg, f.keymap.Done):
-			f.account.txComplete(&tx, true)
+			if !tx.envelope && tx.repeats() && !tx.isFirstOccurrence() {
+				return status("Only the next %s can be marked done", tx.event.Description)
+			}
+
+			prompt := fmt.Sprintf("Mark %s done and change the balance by %s?", tx.event.Description,
+				f.account.currency.FormatMoney(tx.event.Amount))
+			if tx.envelope {
+				prompt = fmt.Sprintf("Log the remaining %s as spent?",
+					f.account.currency.FormatMoney(-tx.event.Amount))
+			}
+
+			return f.confirmation.ask(prompt, func() tea.Cmd {
+				f.remember()
+				f.account.txComplete(&tx, true)
+				return status("Marked %s done", tx.event.Description)
+			})
 		case key.Matches(msg, f.keymap.SetToday):
+			f.remember()
 			f.account.txSetToToday(&tx)
-		case key.Matches(msg, f.keymap.Reload):
-			f.account.reload()
-		case key.Matches(msg, f.keymap.Save):
-			f.account.save()
-		case key.Matches(msg, f.keymap.EditBalance):
+		case key.Matches(msg, f.keymap.Duplicate):
+			if tx.envelope {
+				return status("Envelopes can't be duplicated")
+			}
+
+			f.remember()
+			f.account.duplicateEvent(f.account.findEventIndex(&tx))
+			f.regenerateRows()
+			return status("Duplicated %s", tx.event.Description)
+		case key.Matches(msg, f.keymap.Search):
 			f.table.Blur()
-			f.balance.SetValue(fmt.Sprintf("%.02f", f.account.Balance))
-			f.balance.CursorEnd()
-			f.balance.Focus()
+			f.searchOrigin = f.table.Cursor()
+			f.search.Reset()
+			f.search.Focus()
+			return nil
+		case key.Matches(msg, f.keymap.NextMatch):
+			f.jumpToMatch(f.table.Cursor()+1, 1)
+			return nil
+		case key.Matches(msg, f.keymap.PreviousMatch):
+			f.jumpToMatch(f.table.Cursor()-1, -1)
+			return nil
+		case key.Matches(msg, f.keymap.EditBalance):
+			f.editBalance()
 		}
 	}
 
@@ -336,6 +952,53 @@ func (f *ForecastView) handleTableInput(msg tea.Msg) tea.Cmd {
 	return cmd
 }
 
+func (f *ForecastView) handleChartInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, f.keymap.Help):
+		f.help.ShowAll = !f.help.ShowAll
+	case key.Matches(msg, f.keymap.Chart), key.Matches(msg, f.keymap.FocusTable):
+		f.showChart = false
+	case key.Matches(msg, f.keymap.ChartLeft):
+		f.chart.moveCursor(-1)
+	case key.Matches(msg, f.keymap.ChartRight):
+		f.chart.moveCursor(1)
+	case key.Matches(msg, f.keymap.GotoTop):
+		f.chart.cursorStart()
+	case key.Matches(msg, f.keymap.GotoBottom):
+		f.chart.cursorEnd()
+	case key.Matches(msg, f.keymap.Reload):
+		return f.reload()
+	case key.Matches(msg, f.keymap.Save):
+		return saveAccount(f.account)
+	}
+
+	return nil
+}
+
+func (f *ForecastView) editBalance() {
+	f.table.Blur()
+	f.balance.SetValue(fmt.Sprintf("%.02f", f.account.Balance))
+	f.balance.CursorEnd()
+	f.balance.Focus()
+}
+
+// handleMouse selects the clicked row, scrolls the table with the wheel and edits the balance when
+// it's clicked
+func (f *ForecastView) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if !f.browsing() || f.showChart {
+		return nil
+	}
+
+	// the balance is on the first line of the summary
+	if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress && msg.Y == 0 {
+		f.editBalance()
+		return nil
+	}
+
+	handleTableMouse(&f.table, f.View, msg)
+	return nil
+}
+
 func (f *ForecastView) handleBalanceInput(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -344,10 +1007,14 @@ func (f *ForecastView) handleBalanceInput(msg tea.Msg) tea.Cmd {
 			f.balance.Blur()
 			f.table.Focus()
 		case key.Matches(msg, f.keymap.Confirm):
-			if result, err := strconv.ParseFloat(f.balance.Value(), 32); err == nil {
-				f.account.Balance = float32(result)
+			result, err := evaluate(f.balance.Value())
+			if err != nil {
+				// keep editing so the mistake shown next to the balance can be fixed
+				return nil
 			}
 
+			f.remember()
+			f.account.Balance = float32(result)
 			f.balance.Blur()
 			f.table.Focus()
 		}
@@ -358,6 +1025,84 @@ func (f *ForecastView) handleBalanceInput(msg tea.Msg) tea.Cmd {
 	return cmd
 }
 
+func (f *ForecastView) matchesQuery(tx *Transaction) bool {
+	return f.query != "" && strings.Contains(strings.ToLower(tx.event.Description), strings.ToLower(f.query))
+}
+
+// jumpToMatch moves the cursor to the first row matching the search query, looking from row start in
+// the given direction and wrapping around the ends of the table
+func (f *ForecastView) jumpToMatch(start int, direction int) {
+	count := len(f.transactions)
+	for i := 0; i < count; i++ {
+		row := ((start+i*direction)%count + count) % count
+		if f.matchesQuery(&f.transactions[row]) {
+			f.table.SetCursor(row)
+			return
+		}
+	}
+}
+
+func (f *ForecastView) handleSearchInput(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, f.keymap.FocusTable):
+			f.query = ""
+			f.search.Blur()
+			f.table.SetCursor(f.searchOrigin)
+			f.table.Focus()
+			return nil
+		case key.Matches(msg, f.keymap.Confirm):
+			f.search.Blur()
+			f.table.Focus()
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	f.search, cmd = f.search.Update(msg)
+
+	// search as the query is typed
+	f.query = f.search.Value()
+	f.table.SetCursor(f.searchOrigin)
+	f.jumpToMatch(f.searchOrigin, 1)
+	return cmd
+}
+
+func (f *ForecastView) handleFilterInput(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, f.keymap.FocusTable):
+			f.filterInput.SetValue("")
+			f.filter = TransactionFilter{}
+			f.filterInput.Blur()
+			f.table.Focus()
+			return nil
+		case key.Matches(msg, f.keymap.Confirm):
+			filter, err := parseFilter(f.filterInput.Value())
+			if err != nil {
+				f.filterError = err.Error()
+				return nil
+			}
+
+			f.filter = filter
+			f.filterInput.Blur()
+			f.table.SetCursor(0)
+			f.table.Focus()
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	f.filterInput, cmd = f.filterInput.Update(msg)
+	return cmd
+}
+
 func (f *ForecastView) getSelectedTransaction() *Transaction {
+	if len(f.transactions) == 0 {
+		return nil
+	}
+
 	return &f.transactions[f.table.Cursor()]
 }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Goal is an amount to have saved by a target date, e.g. an emergency fund of $10,000 by June 2027
+type Goal struct {
+	Name   string
+	Target float32
+	Saved  float32
+	Date   time.Time
+}
+
+// parseGoalDate accepts either a full date or just a year and month, in which case the goal is due by
+// the end of that month
+func parseGoalDate(str string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+
+	if date, err := time.ParseInLocation("2006-01-02", str, time.Local); err == nil {
+		return date, nil
+	}
+
+	if date, err := time.ParseInLocation("2006-01", str, time.Local); err == nil {
+		return date.AddDate(0, 1, -1), nil
+	}
+
+	return time.Time{}, fmt.Errorf("goal date must be YYYY-MM or YYYY-MM-DD")
+}
+
+func (g *Goal) contributionDescription() string {
+	return "Transfer to " + g.Name
+}
+
+// monthsRemaining returns the number of monthly contributions that can still be made before the goal
+// is due, starting next month
+func (g *Goal) monthsRemaining() int {
+	now := time.Now()
+	months := (g.Date.Year()-now.Year())*12 + int(g.Date.Month()) - int(now.Month())
+	if months < 1 {
+		return 1
+	}
+
+	return months
+}
+
+// requiredContribution returns how much must be put aside every month to reach the goal in time
+func (g *Goal) requiredContribution() float32 {
+	missing := g.Target - g.Saved
+	if missing <= 0 {
+		return 0
+	}
+
+	return missing / float32(g.monthsRemaining())
+}
+
+// lastContribution returns the last day a contribution may be made on, the goal's due date unless
+// that's too soon for even one
+func (g *Goal) lastContribution() time.Time {
+	if g.Date.Before(nextMonth()) {
+		return nextMonth()
+	}
+
+	return g.Date
+}
+
+func (g *Goal) progress() float64 {
+	if g.Target <= 0 {
+		return 1
+	}
+
+	progress := float64(g.Saved / g.Target)
+	if progress > 1 {
+		return 1
+	}
+
+	return progress
+}
+
+// contributionEvent returns the recurring transfer event funding the goal, if one has been added
+func (a *Account) contributionEvent(goal *Goal) *Event {
+	for i := range a.Events {
+		if a.Events[i].Goal == goal.Name {
+			return &a.Events[i]
+		}
+	}
+
+	return nil
+}
+
+// affords returns whether the forecast stays above the account's minimum balance when the goal's
+// required contribution is paid every month. If the contribution has already been added as an event,
+// the forecast already includes it.
+func (a *Account) affords(goal *Goal) bool {
+	until := forecastHorizon()
+	transactions := a.predict(until)
+
+	if a.contributionEvent(goal) == nil && goal.requiredContribution() > 0 {
+		last := goal.lastContribution()
+		contribution := Event{
+			Date:        nextMonth(),
+			Description: goal.contributionDescription(),
+			Amount:      -goal.requiredContribution(),
+			Frequency:   Monthly,
+			Until:       &last,
+		}
+
+		transactions = append(transactions, contribution.predict(until)...)
+		sort.Sort(byDate(transactions))
+	}
+
+	return a.summarize(transactions).MinimumBalance >= a.BalanceFloor
+}
+
+// addContribution adds the goal's required monthly contribution to the forecast as a recurring
+// transfer that stops once the goal is due, or updates the transfer if it already exists
+func (a *Account) addContribution(goal *Goal) error {
+	contribution := goal.requiredContribution()
+	if contribution <= 0 {
+		return fmt.Errorf("nothing is left to save for %s", goal.Name)
+	}
+
+	until := goal.lastContribution()
+	if event := a.contributionEvent(goal); event != nil {
+		event.Amount = -contribution
+		event.Until = &until
+		return nil
+	}
+
+	a.addEvent(&Event{
+		Date:        nextMonth(),
+		Description: goal.contributionDescription(),
+		Amount:      -contribution,
+		Frequency:   Monthly,
+		Category:    "Savings",
+		Goal:        goal.Name,
+		Until:       &until,
+	})
+	return nil
+}
+
+func (a *Account) findGoal(name string) *Goal {
+	for i := range a.Goals {
+		if a.Goals[i].Name == name {
+			return &a.Goals[i]
+		}
+	}
+
+	return nil
+}
+
+func (a *Account) addGoal(goal Goal) {
+	if existing := a.findGoal(goal.Name); existing != nil {
+		*existing = goal
+		return
+	}
+
+	a.Goals = append(a.Goals, goal)
+}
+
+// updateGoal replaces the goal at index i, keeping its contribution event linked if it was renamed
+func (a *Account) updateGoal(i int, goal Goal) {
+	if event := a.contributionEvent(&a.Goals[i]); event != nil {
+		event.Goal = goal.Name
+		event.Description = goal.contributionDescription()
+	}
+
+	a.Goals[i] = goal
+}
+
+func (a *Account) deleteGoal(i int) {
+	a.Goals = append(a.Goals[:i], a.Goals[i+1:]...)
+}
+
+// nextMonth returns the first day of next month
+func nextMonth() time.Time {
+	return startOfMonth(time.Now()).AddDate(0, 1, 0)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAffords(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	account := func(floor float32) *Account {
+		return &Account{
+			Balance:      100,
+			BalanceFloor: floor,
+			Events: []Event{
+				{ID: "pay", Date: today, Description: "Pay", Amount: 1000, Frequency: Monthly},
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		goal  Goal
+		floor float32
+		want  bool
+	}{
+		{
+			// due well past the forecast horizon, so only the contributions within it count
+			name: "due in three years",
+			goal: Goal{Name: "House", Target: 24000, Date: today.AddDate(3, 0, 0)},
+			want: true,
+		},
+		{
+			name: "more each month than is earned",
+			goal: Goal{Name: "Car", Target: 6000, Date: today.AddDate(0, 3, 0)},
+			want: false,
+		},
+		{
+			name: "already saved",
+			goal: Goal{Name: "Trip", Target: 500, Saved: 500, Date: today.AddDate(1, 0, 0)},
+			want: true,
+		},
+		{
+			name:  "balance kept above the floor",
+			goal:  Goal{Name: "House", Target: 24000, Date: today.AddDate(3, 0, 0)},
+			floor: 1000,
+			want:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := account(test.floor).affords(&test.goal); got != test.want {
+				t.Errorf("affords = %v, want %v (contribution %.2f a month)", got, test.want,
+					test.goal.requiredContribution())
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type GoalViewKeyMap struct {
+	Add             key.Binding
+	Edit            key.Binding
+	Delete          key.Binding
+	AddContribution key.Binding
+	SetFloor        key.Binding
+	PreviousField   key.Binding
+	NextField       key.Binding
+	Help            key.Binding
+	Confirm         key.Binding
+	Cancel          key.Binding
+
+	LineUp     key.Binding
+	LineDown   key.Binding
+	GotoTop    key.Binding
+	GotoBottom key.Binding
+}
+
+func NewGoalViewKeyMap() GoalViewKeyMap {
+	k := GoalViewKeyMap{
+		Add: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "add goal"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit goal"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "delete goal"),
+		),
+		AddContribution: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "add monthly transfer to forecast"),
+		),
+		SetFloor: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "set balance floor"),
+		),
+		PreviousField: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "previous field"),
+		),
+		NextField: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next field"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "confirm"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+	}
+
+	settings.bind("goal", &k)
+	return k
+}
+
+func (k GoalViewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Add, k.AddContribution, k.SetFloor, k.Cancel}
+}
+
+func (k GoalViewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.LineUp, k.LineDown, k.GotoTop, k.GotoBottom},
+		{k.Add, k.Edit, k.Delete, k.AddContribution, k.SetFloor},
+		{k.PreviousField, k.NextField, k.Confirm, k.Cancel},
+	}
+}
+
+type GoalField int
+
+const (
+	goalName GoalField = iota
+	goalTarget
+	goalSaved
+	goalDate
+	goalSentinel
+)
+
+type GoalView struct {
+	keymap GoalViewKeyMap
+	help   help.Model
+
+	table    table.Model
+	progress progress.Model
+	inputs   []textinput.Model
+	floor    textinput.Model
+
+	editing bool
+	index   int // goal being edited, -1 when adding a new goal
+	focused GoalField
+	message string
+	account *Account
+	undo    *UndoHistory
+}
+
+func NewGoalView(account *Account, undo *UndoHistory) GoalView {
+	columns := []table.Column{
+		{Title: "Goal", Width: 25},
+		{Title: "Target", Width: 13},
+		{Title: "Saved", Width: 13},
+		{Title: "Due", Width: 15},
+		{Title: "Monthly", Width: 13},
+		{Title: "Affordable", Width: 10},
+		{Title: "In forecast", Width: 11},
+	}
+
+	style := table.DefaultStyles()
+	style.Header = style.Header.
+		Bold(false).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true)
+
+	style.Selected = style.Selected.
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground).
+		Bold(false)
+
+	t := table.New(
+		table.WithFocused(true),
+		table.WithColumns(columns),
+		table.WithStyles(style),
+	)
+
+	t.KeyMap.PageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.PageDown = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageDown = key.NewBinding(key.WithDisabled())
+
+	keymap := NewGoalViewKeyMap()
+	t.KeyMap.LineUp = keymap.LineUp
+	t.KeyMap.LineDown = keymap.LineDown
+	t.KeyMap.GotoTop = keymap.GotoTop
+	t.KeyMap.GotoBottom = keymap.GotoBottom
+
+	inputs := make([]textinput.Model, goalSentinel)
+
+	inputs[goalName] = textinput.New()
+	inputs[goalName].Prompt = "Name: "
+	inputs[goalName].Placeholder = "Emergency fund"
+
+	inputs[goalTarget] = textinput.New()
+	inputs[goalTarget].Prompt = "Target: $"
+	inputs[goalTarget].Placeholder = "10000.00"
+	inputs[goalTarget].Validate = validateExpression
+
+	inputs[goalSaved] = textinput.New()
+	inputs[goalSaved].Prompt = "Saved so far: $"
+	inputs[goalSaved].Placeholder = "0.00"
+	inputs[goalSaved].Validate = validateExpression
+
+	inputs[goalDate] = textinput.New()
+	inputs[goalDate].Prompt = "Due by: "
+	inputs[goalDate].Placeholder = "2027-06"
+	inputs[goalDate].CharLimit = 10
+
+	floor := textinput.New()
+	floor.Prompt = "Balance floor: "
+	floor.Validate = validateExpression
+
+	return GoalView{
+		keymap: keymap,
+		help:   help.New(),
+
+		table:    t,
+		progress: progress.New(progress.WithDefaultGradient(), progress.WithWidth(40)),
+		inputs:   inputs,
+		floor:    floor,
+
+		account: account,
+		undo:    undo,
+	}
+}
+
+// browsing returns whether the view is showing the list of goals rather than editing one or the
+// balance floor
+func (g *GoalView) browsing() bool {
+	return !g.editing && !g.floor.Focused()
+}
+
+func (g *GoalView) regenerateRows() {
+	rows := make([]table.Row, 0, len(g.account.Goals))
+	for i := range g.account.Goals {
+		goal := &g.account.Goals[i]
+
+		affordable := "no"
+		if g.account.affords(goal) {
+			affordable = "yes"
+		}
+
+		in_forecast := "no"
+		if g.account.contributionEvent(goal) != nil {
+			in_forecast = "yes"
+		}
+
+		rows = append(rows, table.Row{
+			goal.Name,
+			g.account.currency.FormatMoney(goal.Target),
+			g.account.currency.FormatMoney(goal.Saved),
+			goal.Date.Format("January 2006"),
+			g.account.currency.FormatMoney(goal.requiredContribution()),
+			affordable,
+			in_forecast,
+		})
+	}
+
+	g.table.SetHeight(len(rows))
+	g.table.SetRows(rows)
+	g.table.SetCursor(g.table.Cursor())
+}
+
+func (g *GoalView) selected() *Goal {
+	if len(g.account.Goals) == 0 {
+		return nil
+	}
+
+	return &g.account.Goals[g.table.Cursor()]
+}
+
+// setAccount switches the goals to another account, either the real one or a scenario's sandbox
+func (g *GoalView) setAccount(account *Account) {
+	g.account = account
+}
+
+func (g *GoalView) browse() {
+	g.editing = false
+	for i := range g.inputs {
+		g.inputs[i].Blur()
+	}
+	g.table.Focus()
+	g.regenerateRows()
+}
+
+func (g *GoalView) edit(goal *Goal) {
+	g.editing = true
+	g.index = -1
+	g.message = ""
+	g.table.Blur()
+
+	for i := range g.inputs {
+		g.inputs[i].Reset()
+	}
+
+	if goal != nil {
+		g.index = g.table.Cursor()
+		g.inputs[goalName].SetValue(goal.Name)
+		g.inputs[goalTarget].SetValue(fmt.Sprintf("%.02f", goal.Target))
+		g.inputs[goalSaved].SetValue(fmt.Sprintf("%.02f", goal.Saved))
+		g.inputs[goalDate].SetValue(goal.Date.Format("2006-01-02"))
+	}
+
+	g.focused = goalName
+	g.focus()
+}
+
+func (g *GoalView) focus() {
+	for i := range g.inputs {
+		g.inputs[i].Blur()
+	}
+
+	g.inputs[g.focused].Focus()
+}
+
+// getGoal builds a goal from the inputs, reporting which input is invalid if any
+func (g *GoalView) getGoal() (Goal, error) {
+	name := strings.TrimSpace(g.inputs[goalName].Value())
+	if name == "" {
+		return Goal{}, fmt.Errorf("goal must have a name")
+	}
+
+	target, err := evaluate(g.inputs[goalTarget].Value())
+	if err != nil {
+		return Goal{}, fmt.Errorf("target: %w", err)
+	}
+	if target <= 0 {
+		return Goal{}, fmt.Errorf("target must be a positive amount")
+	}
+
+	var saved float64
+	if g.inputs[goalSaved].Value() != "" {
+		if saved, err = evaluate(g.inputs[goalSaved].Value()); err != nil {
+			return Goal{}, fmt.Errorf("saved so far: %w", err)
+		}
+	}
+
+	date, err := parseGoalDate(g.inputs[goalDate].Value())
+	if err != nil {
+		return Goal{}, err
+	}
+
+	return Goal{
+		Name:   name,
+		Target: float32(target),
+		Saved:  float32(saved),
+		Date:   date,
+	}, nil
+}
+
+func (g *GoalView) View() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Savings goals, affordable if the balance stays above %s",
+		g.account.currency.FormatMoney(g.account.BalanceFloor)))
+	b.WriteString("\n\n")
+	b.WriteString(g.table.View())
+	b.WriteString("\n\n")
+
+	if g.floor.Focused() {
+		b.WriteString(g.floor.View())
+		b.WriteString("\n\n")
+	} else if g.editing {
+		for i := range g.inputs {
+			b.WriteString(g.inputs[i].View())
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	} else if goal := g.selected(); goal != nil {
+		b.WriteString(goal.Name)
+		b.WriteString("\n")
+		b.WriteString(g.progress.ViewAs(goal.progress()))
+		b.WriteString("\n\n")
+	}
+
+	if g.message != "" {
+		b.WriteString(g.message)
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(g.help.View(g.keymap))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (g *GoalView) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		g.help.Width = msg.Width
+	case tea.MouseMsg:
+		if g.browsing() {
+			handleTableMouse(&g.table, g.View, msg)
+		}
+	case tea.KeyMsg:
+		if g.floor.Focused() {
+			return g.handleFloorInput(msg)
+		}
+		if g.editing {
+			return g.handleEditInput(msg)
+		}
+
+		return g.handleTableInput(msg)
+	}
+
+	return nil
+}
+
+func (g *GoalView) handleTableInput(msg tea.KeyMsg) tea.Cmd {
+	g.message = ""
+
+	switch {
+	case key.Matches(msg, g.keymap.Help):
+		g.help.ShowAll = !g.help.ShowAll
+	case key.Matches(msg, g.keymap.Add):
+		g.edit(nil)
+		return nil
+	case key.Matches(msg, g.keymap.Edit):
+		if goal := g.selected(); goal != nil {
+			g.edit(goal)
+		}
+		return nil
+	case key.Matches(msg, g.keymap.Delete):
+		if g.selected() != nil {
+			g.undo.remember(g.account)
+			g.account.deleteGoal(g.table.Cursor())
+			if g.table.Cursor() >= len(g.account.Goals) && g.table.Cursor() > 0 {
+				g.table.SetCursor(g.table.Cursor() - 1)
+			}
+			g.regenerateRows()
+		}
+		return nil
+	case key.Matches(msg, g.keymap.AddContribution):
+		if goal := g.selected(); goal != nil {
+			g.undo.remember(g.account)
+			if err := g.account.addContribution(goal); err != nil {
+				g.undo.undo(g.account)
+				g.message = err.Error()
+				return nil
+			}
+
+			g.message = fmt.Sprintf("Added a monthly transfer of %s to the forecast until %s",
+				g.account.currency.FormatMoney(goal.requiredContribution()),
+				goal.lastContribution().Format("January 2006"))
+			g.regenerateRows()
+		}
+		return nil
+	case key.Matches(msg, g.keymap.SetFloor):
+		g.table.Blur()
+		g.floor.SetValue(fmt.Sprintf("%.02f", g.account.BalanceFloor))
+		g.floor.CursorEnd()
+		g.floor.Focus()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	g.table, cmd = g.table.Update(msg)
+	return cmd
+}
+
+func (g *GoalView) handleFloorInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, g.keymap.Cancel):
+		g.floor.Blur()
+		g.table.Focus()
+		return nil
+	case key.Matches(msg, g.keymap.Confirm):
+		floor, err := evaluate(g.floor.Value())
+		if err != nil {
+			g.message = err.Error()
+			return nil
+		}
+
+		// whether each goal is affordable depends on the floor
+		g.message = ""
+		g.undo.remember(g.account)
+		g.account.BalanceFloor = float32(floor)
+		g.floor.Blur()
+		g.browse()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	g.floor, cmd = g.floor.Update(msg)
+	return cmd
+}
+
+func (g *GoalView) handleEditInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, g.keymap.Cancel):
+		g.message = ""
+		g.browse()
+		return nil
+	case key.Matches(msg, g.keymap.NextField):
+		g.focused = (g.focused + 1) % goalSentinel
+		g.focus()
+		return nil
+	case key.Matches(msg, g.keymap.PreviousField):
+		g.focused = (g.focused + goalSentinel - 1) % goalSentinel
+		g.focus()
+		return nil
+	case key.Matches(msg, g.keymap.Confirm):
+		goal, err := g.getGoal()
+		if err != nil {
+			g.message = err.Error()
+			return nil
+		}
+
+		g.message = ""
+		g.undo.remember(g.account)
+		if g.index >= 0 {
+			g.account.updateGoal(g.index, goal)
+		} else {
+			g.account.addGoal(goal)
+		}
+		g.browse()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	g.inputs[g.focused], cmd = g.inputs[g.focused].Update(msg)
+	return cmd
+}
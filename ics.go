@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+func (f Frequency) toRecurrenceRule() string {
+	switch f {
+	case Daily:
+		return "FREQ=DAILY"
+	case Weekly:
+		return "FREQ=WEEKLY"
+	case Biweekly:
+		return "FREQ=WEEKLY;INTERVAL=2"
+	case Monthly:
+		return "FREQ=MONTHLY"
+	case Yearly:
+		return "FREQ=YEARLY"
+	}
+
+	return ""
+}
+
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes a single content line, folding it at 75 octets and terminating it with CRLF as
+// required by RFC 5545
+func (i *icsWriter) line(format string, a ...interface{}) {
+	if i.err != nil {
+		return
+	}
+
+	content := fmt.Sprintf(format, a...)
+	limit := 75
+	for len(content) > limit {
+		split := limit
+		// don't split a multibyte character across lines
+		for split > 0 && content[split]&0xc0 == 0x80 {
+			split--
+		}
+
+		if _, i.err = io.WriteString(i.w, content[:split]+"\r\n "); i.err != nil {
+			return
+		}
+
+		content = content[split:]
+		// continuation lines start with a space which counts towards the limit
+		limit = 74
+	}
+
+	_, i.err = io.WriteString(i.w, content+"\r\n")
+}
+
+func icsEscape(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return replacer.Replace(text)
+}
+
+func icsSummary(account *Account, event *Event) string {
+	return fmt.Sprintf("%s (%s)", event.Description, account.currency.FormatMoney(event.Amount))
+}
+
+// writeICS writes the account's events as an iCalendar file. By default each event becomes a single
+// VEVENT with a recurrence rule. When occurrences is set, every projected transaction is instead
+// written as its own VEVENT carrying the running balance after it, since a recurring series can't
+// describe a different balance for each occurrence.
+func writeICS(w io.Writer, account *Account, transactions []Transaction, occurrences bool) error {
+	i := icsWriter{w: w}
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	i.line("BEGIN:VCALENDAR")
+	i.line("VERSION:2.0")
+	i.line("PRODID:-//forecash//forecash//EN")
+	i.line("CALSCALE:GREGORIAN")
+	i.line("X-WR-CALNAME:forecash")
+
+	if occurrences {
+		balance := account.Balance
+		for _, transaction := range transactions {
+			balance += transaction.event.Amount
+
+			i.line("BEGIN:VEVENT")
+			i.line("UID:%x-%s@forecash", transaction.hash, transaction.date.Format("20060102"))
+			i.line("DTSTAMP:%s", stamp)
+			i.line("DTSTART;VALUE=DATE:%s", transaction.date.Format("20060102"))
+			i.line("SUMMARY:%s", icsEscape(icsSummary(account, transaction.event)))
+			i.line("DESCRIPTION:%s", icsEscape(fmt.Sprintf("Projected balance: %s",
+				account.currency.FormatMoney(balance))))
+			i.line("TRANSP:TRANSPARENT")
+			i.line("END:VEVENT")
+		}
+	} else {
+		for e := range account.Events {
+			event := &account.Events[e]
+
+			// a paused series starts again when it resumes. One paused for good, or that only happens
+			// once, has nothing to show.
+			if event.Paused && (event.ResumeOn == nil || event.Frequency == Once) {
+				continue
+			}
+
+			start := event.firstOccurrence()
+			if event.Until != nil && start.After(*event.Until) {
+				continue
+			}
+
+			// the transaction hash identifies an event by its ID
+			t := Transaction{event: event}
+			t.calculateHash()
+
+			i.line("BEGIN:VEVENT")
+			i.line("UID:%x@forecash", t.hash)
+			i.line("DTSTAMP:%s", stamp)
+			i.line("DTSTART;VALUE=DATE:%s", start.Format("20060102"))
+			if rule := event.Frequency.toRecurrenceRule(); rule != "" {
+				if event.Until != nil {
+					rule += ";UNTIL=" + event.Until.Format("20060102")
+				}
+				i.line("RRULE:%s", rule)
+			}
+			i.line("SUMMARY:%s", icsEscape(icsSummary(account, event)))
+			i.line("TRANSP:TRANSPARENT")
+			i.line("END:VEVENT")
+		}
+	}
+
+	i.line("END:VCALENDAR")
+	return i.err
+}
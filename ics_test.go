@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICSSkipsPausedOccurrences(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.Local)
+	}
+	resume := date(time.November, 1)
+
+	account := &Account{
+		Events: []Event{
+			{ID: "pay", Date: date(time.October, 16), Description: "Pay", Amount: 1000,
+				Frequency: Biweekly, Paused: true, ResumeOn: &resume},
+			{ID: "gym", Date: date(time.October, 20), Description: "Gym", Amount: -40,
+				Frequency: Monthly, Paused: true},
+			{ID: "gift", Date: date(time.October, 25), Description: "Gift", Amount: -50,
+				Frequency: Once, Paused: true, ResumeOn: &resume},
+			{ID: "rent", Date: date(time.October, 25), Description: "Rent", Amount: -800,
+				Frequency: Monthly},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		occurrences bool
+		want        []string
+	}{
+		{
+			name: "events",
+			want: []string{"DTSTART;VALUE=DATE:20261113", "DTSTART;VALUE=DATE:20261025"},
+		},
+		{
+			name:        "occurrences",
+			occurrences: true,
+			want: []string{"DTSTART;VALUE=DATE:20261025", "DTSTART;VALUE=DATE:20261113",
+				"DTSTART;VALUE=DATE:20261125", "DTSTART;VALUE=DATE:20261127"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b strings.Builder
+			transactions := account.predict(date(time.December, 1))
+			if err := writeICS(&b, account, transactions, test.occurrences); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, line := range strings.Split(b.String(), "\r\n") {
+				if strings.HasPrefix(line, "DTSTART") {
+					got = append(got, line)
+				}
+			}
+
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+
+			for _, skipped := range []string{"Gym", "Gift"} {
+				if strings.Contains(b.String(), skipped) {
+					t.Errorf("paused %s is in the calendar", skipped)
+				}
+			}
+		})
+	}
+}
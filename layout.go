@@ -0,0 +1,205 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ColumnSpec describes how a table column may be sized. Columns start at their minimum width and grow
+// in proportion towards their preferred width as the terminal allows. Any space left over after that
+// goes to the column marked to fill, up to its maximum.
+type ColumnSpec struct {
+	Title     string
+	Min       int
+	Preferred int
+	Max       int
+	Fill      bool
+
+	// optional columns are hidden, last first, when the terminal is too narrow for all columns
+	Optional bool
+}
+
+// every cell is padded by one space on either side
+const cellPadding = 2
+
+// layoutColumns sizes the columns to fit within the given width. It returns the columns to show and,
+// for each of them, the index of the spec it was made from.
+func layoutColumns(specs []ColumnSpec, width int) ([]table.Column, []int) {
+	visible := make([]int, 0, len(specs))
+	total := 0
+	for i, spec := range specs {
+		visible = append(visible, i)
+		total += spec.Min + cellPadding
+	}
+
+	// drop optional columns from the end until the rest fit
+	for i := len(visible) - 1; i >= 0 && total > width; i-- {
+		spec := specs[visible[i]]
+		if !spec.Optional {
+			continue
+		}
+
+		total -= spec.Min + cellPadding
+		visible = append(visible[:i], visible[i+1:]...)
+	}
+
+	widths := make([]int, len(visible))
+	growth := 0
+	for i, index := range visible {
+		widths[i] = specs[index].Min
+		growth += specs[index].Preferred - specs[index].Min
+	}
+
+	extra := width - total
+	if extra > 0 && growth > 0 {
+		share := extra
+		if share > growth {
+			share = growth
+		}
+
+		for i, index := range visible {
+			widths[i] += (specs[index].Preferred - specs[index].Min) * share / growth
+		}
+
+		extra -= share
+	}
+
+	if extra > 0 {
+		for i, index := range visible {
+			spec := specs[index]
+			if !spec.Fill {
+				continue
+			}
+
+			widths[i] += extra
+			if spec.Max > 0 && widths[i] > spec.Max {
+				widths[i] = spec.Max
+			}
+		}
+	}
+
+	columns := make([]table.Column, len(visible))
+	for i, index := range visible {
+		columns[i] = table.Column{Title: specs[index].Title, Width: widths[i]}
+	}
+
+	return columns, visible
+}
+
+// selectColumns returns the cells of the row belonging to the visible columns
+func selectColumns(row table.Row, visible []int) table.Row {
+	result := make(table.Row, len(visible))
+	for i, index := range visible {
+		result[i] = row[index]
+	}
+
+	return result
+}
+
+// tableWidth returns the width the table takes up on screen
+func tableWidth(columns []table.Column) int {
+	width := 0
+	for _, column := range columns {
+		width += column.Width + cellPadding
+	}
+
+	return width
+}
+
+// tables are drawn below their column titles and the border under them
+const tableHeaderLines = 2
+
+// fitRows returns how many of the rows fit on a screen of the given height alongside the other
+// lines the view draws and the status line below it. At least one row is always shown.
+func fitRows(rows int, screen int, lines int) int {
+	height := screen - lines - 1
+	if height < 1 {
+		height = 1
+	}
+	if rows < height {
+		height = rows
+	}
+
+	return height
+}
+
+// handleTableMouse scrolls the table with the mouse wheel and moves its cursor to the row that was
+// clicked, returning whether a row was. Since the table doesn't know where it's drawn, it's looked
+// for in the view it's part of.
+func handleTableMouse(t *table.Model, view func() string, msg tea.MouseMsg) bool {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		t.MoveUp(1)
+	case msg.Button == tea.MouseButtonWheelDown:
+		t.MoveDown(1)
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		if top := tableTop(*t, view()); top >= 0 {
+			if row := tableRowAt(*t, msg.Y-top); row >= 0 {
+				moveTableCursor(t, row)
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// tableTop returns the line of the view the table starts on, or -1 if it isn't shown
+func tableTop(t table.Model, view string) int {
+	i := strings.Index(view, t.View())
+	if i < 0 {
+		return -1
+	}
+
+	return strings.Count(view[:i], "\n")
+}
+
+// tableRowAt returns the index of the row shown on line y of the table's view, counting from the top
+// of its header, or -1 if no row is shown there
+func tableRowAt(t table.Model, y int) int {
+	line := y - (lipgloss.Height(t.View()) - t.Height())
+	if line < 0 || line >= t.Height() || len(t.Rows()) == 0 {
+		return -1
+	}
+
+	// the table doesn't tell which rows it's scrolled to, so an unstyled copy of it is given row
+	// numbers to show instead and the number on that line is read back. The numbers go in one column
+	// at a time since a column may be too narrow for them.
+	probe := t
+	probe.SetStyles(table.Styles{})
+
+	for column := range t.Rows()[0] {
+		rows := make([]table.Row, len(t.Rows()))
+		for i := range rows {
+			rows[i] = make(table.Row, len(t.Rows()[0]))
+			rows[i][column] = strconv.Itoa(i)
+		}
+		probe.SetRows(rows)
+
+		// without styles the header takes a single line
+		lines := strings.Split(probe.View(), "\n")
+		if line+1 >= len(lines) {
+			return -1
+		}
+
+		if row, err := strconv.Atoi(strings.TrimSpace(lines[line+1])); err == nil {
+			return row
+		}
+	}
+
+	return -1
+}
+
+// moveTableCursor moves the cursor to the given row the way the arrow keys do, so that the table
+// stays scrolled where it is
+func moveTableCursor(t *table.Model, row int) {
+	if row < t.Cursor() {
+		t.MoveUp(t.Cursor() - row)
+	} else if row > t.Cursor() {
+		t.MoveDown(row - t.Cursor())
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Status flags used by both hledger and Beancount: completed entries are cleared, projected entries
+// are pending.
+const (
+	ledgerCleared = "*"
+	ledgerPending = "!"
+)
+
+type ledgerEntry struct {
+	date          time.Time
+	status        string
+	description   string
+	amount        float32
+	ledgerAccount string
+}
+
+func ledgerEntries(account *Account, transactions []Transaction, history bool) []ledgerEntry {
+	entries := []ledgerEntry{}
+
+	if history {
+		for _, h := range account.History {
+			entry := ledgerEntry{
+				date:          h.Date,
+				status:        ledgerCleared,
+				description:   h.Description,
+				amount:        h.Amount,
+				ledgerAccount: h.LedgerAccount,
+			}
+
+			if entry.ledgerAccount == "" {
+				event := Event{Amount: h.Amount}
+				entry.ledgerAccount = event.ledgerAccount()
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, transaction := range transactions {
+		entries = append(entries, ledgerEntry{
+			date:          transaction.date,
+			status:        ledgerPending,
+			description:   transaction.event.Description,
+			amount:        transaction.event.Amount,
+			ledgerAccount: transaction.event.ledgerAccount(),
+		})
+	}
+
+	return entries
+}
+
+// writeHledger writes entries in hledger journal syntax, with the event's account receiving the
+// amount in the account's commodity and the account's asset account balancing the posting
+func writeHledger(w io.Writer, account *Account, entries []ledgerEntry) error {
+	commodity := account.commodity()
+
+	for _, entry := range entries {
+		_, err := fmt.Fprintf(w, "%s %s %s\n    %-40s  %.2f %s\n    %s\n\n",
+			entry.date.Format("2006-01-02"),
+			entry.status,
+			entry.description,
+			entry.ledgerAccount,
+			-entry.amount,
+			commodity,
+			account.ledgerAccount())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeBeancount writes entries in Beancount syntax. Beancount requires an open directive for every
+// account used, so those are emitted first, dated on the earliest entry.
+func writeBeancount(w io.Writer, account *Account, entries []ledgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	asset := beancountAccount(account.ledgerAccount())
+	commodity := account.commodity()
+
+	opened := map[string]bool{asset: true}
+	accounts := []string{asset}
+	earliest := entries[0].date
+	for _, entry := range entries {
+		name := beancountAccount(entry.ledgerAccount)
+		if !opened[name] {
+			opened[name] = true
+			accounts = append(accounts, name)
+		}
+
+		if entry.date.Before(earliest) {
+			earliest = entry.date
+		}
+	}
+
+	for _, name := range accounts {
+		if _, err := fmt.Fprintf(w, "%s open %s\n", earliest.Format("2006-01-02"), name); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		description := strings.ReplaceAll(entry.description, `"`, `'`)
+
+		_, err := fmt.Fprintf(w, "\n%s %s \"%s\"\n  %-40s  %.2f %s\n  %-40s  %.2f %s\n",
+			entry.date.Format("2006-01-02"),
+			entry.status,
+			description,
+			beancountAccount(entry.ledgerAccount),
+			-entry.amount,
+			commodity,
+			asset,
+			entry.amount,
+			commodity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// beancountAccount converts an hledger style account name such as "expenses:car fuel" into one
+// Beancount accepts, "Expenses:Car-Fuel": each component must start with a capital letter and may
+// only contain letters, numbers and dashes.
+func beancountAccount(name string) string {
+	components := strings.Split(name, ":")
+	for i, component := range components {
+		var b strings.Builder
+		capitalize := true
+
+		for _, r := range strings.TrimSpace(component) {
+			switch {
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				if capitalize {
+					r = unicode.ToUpper(r)
+					capitalize = false
+				}
+				b.WriteRune(r)
+			default:
+				if b.Len() > 0 && !capitalize {
+					b.WriteRune('-')
+				}
+				capitalize = true
+			}
+		}
+
+		components[i] = strings.TrimSuffix(b.String(), "-")
+		if components[i] == "" {
+			components[i] = "Unknown"
+		}
+	}
+
+	// Beancount only allows these five root accounts
+	switch components[0] {
+	case "Assets", "Liabilities", "Equity", "Income", "Expenses":
+	default:
+		components = append([]string{"Expenses"}, components...)
+	}
+
+	return strings.Join(components, ":")
+}
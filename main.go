@@ -2,11 +2,25 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 )
 
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [command [arguments]]\n\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "Commands:\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  summary\tprint the minimum forecasted balance and safe to spend amount\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  hledger\texport history and projected transactions as an hledger journal\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  beancount\texport history and projected transactions as a Beancount ledger\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  ics\t\texport events as an iCalendar file\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  import\treconcile a bank statement against the forecast\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  \t\timport [flags] file.{csv,ofx,qfx}, see import -h for the flags\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\nWith no command, the interactive interface is started.\n\nFlags:\n")
+	flag.PrintDefaults()
+}
+
 func main() {
 	homedir, err := os.UserHomeDir()
 	if err != nil {
@@ -14,7 +28,12 @@ func main() {
 	}
 	default_config_path := filepath.Join(homedir, ".config", "forecash", "account.json")
 
+	default_settings_path := filepath.Join(homedir, ".config", "forecash", "settings.json")
+
 	config_path := flag.String("config", default_config_path, "account configuration file")
+	settings_path := flag.String("settings", default_settings_path,
+		"interface settings file with key bindings and theme")
+	flag.Usage = usage
 	flag.Parse()
 
 	config_directory := filepath.Dir(*config_path)
@@ -31,7 +50,40 @@ func main() {
 		log.Printf("Created configuration file: %s", *config_path)
 	}
 
-	account := newAccount(config_path)
-	tui := newTui(&account)
-	tui.run()
+	account, err := newAccount(config_path)
+	if err != nil {
+		log.Fatalf("Error reading configuration file: %v", err)
+	}
+
+	switch flag.Arg(0) {
+	case "":
+		useSettings(*settings_path)
+		tui := newTui(&account)
+		tui.run()
+	case "summary":
+		runSummary(&account, flag.Args()[1:])
+	case "hledger", "beancount":
+		runLedgerExport(&account, flag.Arg(0), flag.Args()[1:])
+	case "ics":
+		runICS(&account, flag.Args()[1:])
+	case "import":
+		// reviewing the import opens the interface
+		useSettings(*settings_path)
+		runImport(&account, flag.Args()[1:])
+	default:
+		fmt.Fprintf(flag.CommandLine.Output(), "Unknown command: %s\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// useSettings loads the key bindings and theme the interface is drawn with
+func useSettings(path string) {
+	var err error
+	if settings, err = loadSettings(path); err != nil {
+		log.Fatalf("Error in settings file %s: %v", path, err)
+	}
+	if theme, err = settings.theme(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OFXStatement holds the parts of an OFX bank statement forecash cares about
+type OFXStatement struct {
+	Transactions []BankTransaction
+
+	// the ledger balance is only present if the statement contained a LEDGERBAL aggregate
+	HasBalance  bool
+	Balance     float32
+	BalanceDate time.Time
+}
+
+// parseOFX reads an OFX or QFX bank statement. OFX 1.x is SGML where elements holding data aren't
+// closed while OFX 2.x is XML where they are. Both are handled by treating the contents of an element
+// as everything up to the next tag, which is the same in either format, and ignoring closing tags of
+// data elements.
+func parseOFX(r io.Reader) (OFXStatement, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return OFXStatement{}, err
+	}
+
+	document := string(content)
+	start := strings.Index(strings.ToUpper(document), "<OFX>")
+	if start < 0 {
+		return OFXStatement{}, fmt.Errorf("not an OFX file: no <OFX> element found")
+	}
+	document = document[start:]
+
+	var statement OFXStatement
+	var transaction *BankTransaction
+	var name string
+	var memo string
+	in_balance := false
+
+	for len(document) > 0 {
+		open := strings.IndexByte(document, '<')
+		if open < 0 {
+			break
+		}
+
+		end := strings.IndexByte(document[open:], '>')
+		if end < 0 {
+			return OFXStatement{}, fmt.Errorf("unterminated tag in OFX file")
+		}
+		end += open
+
+		tag := strings.ToUpper(strings.TrimSpace(document[open+1 : end]))
+		document = document[end+1:]
+
+		// the value of a data element is everything up to the next tag
+		value := document
+		if next := strings.IndexByte(document, '<'); next >= 0 {
+			value = document[:next]
+		}
+		value = strings.TrimSpace(value)
+
+		switch tag {
+		case "STMTTRN":
+			transaction = &BankTransaction{}
+			name = ""
+			memo = ""
+		case "/STMTTRN":
+			if transaction == nil {
+				continue
+			}
+
+			// NAME is limited to 32 characters so MEMO often holds the useful part of the description
+			transaction.Description = name
+			if memo != "" && !strings.Contains(name, memo) {
+				transaction.Description = strings.TrimSpace(name + " " + memo)
+			}
+
+			statement.Transactions = append(statement.Transactions, *transaction)
+			transaction = nil
+		case "LEDGERBAL":
+			in_balance = true
+		case "/LEDGERBAL":
+			in_balance = false
+		case "DTPOSTED":
+			if transaction != nil {
+				if transaction.Date, err = parseOFXDate(value); err != nil {
+					return OFXStatement{}, err
+				}
+			}
+		case "TRNAMT":
+			if transaction != nil {
+				if transaction.Amount, err = parseOFXAmount(value); err != nil {
+					return OFXStatement{}, err
+				}
+			}
+		case "NAME":
+			name = ofxUnescape(value)
+		case "MEMO":
+			memo = ofxUnescape(value)
+		case "BALAMT":
+			if in_balance {
+				if statement.Balance, err = parseOFXAmount(value); err != nil {
+					return OFXStatement{}, err
+				}
+				statement.HasBalance = true
+			}
+		case "DTASOF":
+			if in_balance {
+				if statement.BalanceDate, err = parseOFXDate(value); err != nil {
+					return OFXStatement{}, err
+				}
+			}
+		}
+	}
+
+	return statement, nil
+}
+
+// parseOFXDate parses an OFX datetime, YYYYMMDD optionally followed by HHMMSS, milliseconds and a
+// timezone such as [-5:EST]. Only the date is kept since forecash works in whole days.
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid OFX date: %q", value)
+	}
+
+	date, err := time.ParseInLocation("20060102", value[:8], time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid OFX date: %q", value)
+	}
+
+	return date, nil
+}
+
+func parseOFXAmount(value string) (float32, error) {
+	// some locales use a comma as the decimal separator, which the OFX specification allows
+	value = strings.Replace(value, ",", ".", 1)
+
+	result, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid OFX amount: %q", value)
+	}
+
+	return float32(result), nil
+}
+
+func ofxUnescape(value string) string {
+	replacer := strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'")
+	return replacer.Replace(value)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOFX(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		file         string
+		transactions []BankTransaction
+		balance      float32
+		balanceDate  time.Time
+	}{
+		{
+			file: "testdata/statement_v1.ofx",
+			transactions: []BankTransaction{
+				{date(2026, time.October, 1), "PROPERTY MGMT CO RENT OCTOBER", -1850},
+				{date(2026, time.October, 15), "ACME CORP PAYROLL", 2450},
+				{date(2026, time.October, 16), "CORNER CAFE & BAKERY", -4.75},
+			},
+			balance:     3120.42,
+			balanceDate: date(2026, time.October, 18),
+		},
+		{
+			file: "testdata/statement_v2.qfx",
+			transactions: []BankTransaction{
+				{date(2026, time.October, 3), "CITY UTILITIES ELECTRIC AUTOPAY", -89.99},
+				{date(2026, time.October, 15), "ACME CORP PAYROLL", 2450},
+			},
+			balance:     4210.17,
+			balanceDate: date(2026, time.October, 18),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			f, err := os.Open(test.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			statement, err := parseOFX(f)
+			if err != nil {
+				t.Fatalf("parseOFX: %v", err)
+			}
+
+			if len(statement.Transactions) != len(test.transactions) {
+				t.Fatalf("got %d transactions, want %d: %+v", len(statement.Transactions),
+					len(test.transactions), statement.Transactions)
+			}
+
+			for i, want := range test.transactions {
+				got := statement.Transactions[i]
+				if !got.Date.Equal(want.Date) || got.Description != want.Description ||
+					got.Amount != want.Amount {
+					t.Errorf("transaction %d: got %+v, want %+v", i, got, want)
+				}
+			}
+
+			if !statement.HasBalance {
+				t.Fatalf("no ledger balance, want %.2f", test.balance)
+			}
+			if statement.Balance != test.balance {
+				t.Errorf("ledger balance: got %.2f, want %.2f", statement.Balance, test.balance)
+			}
+			if !statement.BalanceDate.Equal(test.balanceDate) {
+				t.Errorf("ledger balance date: got %v, want %v", statement.BalanceDate, test.balanceDate)
+			}
+		})
+	}
+}
+
+func TestParseOFXRejectsOtherFiles(t *testing.T) {
+	if _, err := parseOFX(strings.NewReader("Date,Description,Amount\n")); err == nil {
+		t.Error("expected an error for input without an <OFX> element")
+	}
+}
+
+func TestParseOFXDate(t *testing.T) {
+	want := time.Date(2026, time.October, 15, 0, 0, 0, 0, time.Local)
+
+	for _, value := range []string{"20261015", "20261015120000", "20261015120000.000[-5:EST]"} {
+		got, err := parseOFXDate(value)
+		if err != nil {
+			t.Errorf("parseOFXDate(%q): %v", value, err)
+		} else if !got.Equal(want) {
+			t.Errorf("parseOFXDate(%q) = %v, want %v", value, got, want)
+		}
+	}
+
+	for _, value := range []string{"", "2026", "2026-10-15"} {
+		if _, err := parseOFXDate(value); err == nil {
+			t.Errorf("parseOFXDate(%q): expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type PausePromptKeyMap struct {
+	Confirm key.Binding
+	Cancel  key.Binding
+}
+
+func NewPausePromptKeyMap() PausePromptKeyMap {
+	k := PausePromptKeyMap{
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "pause"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+
+	settings.bind("pause", &k)
+	return k
+}
+
+// PausePrompt asks when an event being paused should resume. Without a date the event stays paused
+// until it's resumed by hand.
+type PausePrompt struct {
+	keymap PausePromptKeyMap
+	input  textinput.Model
+
+	pending func(resume *time.Time) tea.Cmd
+}
+
+func NewPausePrompt() PausePrompt {
+	input := textinput.New()
+	input.Placeholder = "leave empty to pause until resumed, or " + dateHelp
+
+	return PausePrompt{
+		keymap: NewPausePromptKeyMap(),
+		input:  input,
+	}
+}
+
+// toggle resumes event i if it's paused and otherwise asks when it should resume before pausing it.
+// changing is called just before the event is changed.
+func (p *PausePrompt) toggle(account *Account, i int, changing func()) tea.Cmd {
+	event := &account.Events[i]
+	name := event.Description
+
+	if event.Paused {
+		changing()
+		account.resumeEvent(i)
+		return status("Resumed %s", name)
+	}
+
+	p.pending = func(resume *time.Time) tea.Cmd {
+		changing()
+		account.pauseEvent(i, resume)
+		if resume == nil {
+			return status("Paused %s", name)
+		}
+		return status("Paused %s until %s", name, resume.Format("January 2, 2006"))
+	}
+
+	p.input.Prompt = fmt.Sprintf("Resume %s on: ", name)
+	p.input.Reset()
+	p.input.Focus()
+	return nil
+}
+
+// active returns whether the resume date is being asked for
+func (p *PausePrompt) active() bool {
+	return p.input.Focused()
+}
+
+// parse returns the resume date typed so far, nil when there is none
+func (p *PausePrompt) parse() (*time.Time, error) {
+	if strings.TrimSpace(p.input.Value()) == "" {
+		return nil, nil
+	}
+
+	now := time.Now()
+	resume, err := parseDate(p.input.Value(), now)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resume.After(startOfDay(now)) {
+		return nil, fmt.Errorf("the resume date must be after today")
+	}
+
+	return &resume, nil
+}
+
+// Update returns whether the message was meant for the prompt, along with the command to run
+func (p *PausePrompt) Update(msg tea.Msg) (bool, tea.Cmd) {
+	keypress, ok := msg.(tea.KeyMsg)
+	if !ok || !p.active() {
+		return false, nil
+	}
+
+	switch {
+	case key.Matches(keypress, p.keymap.Cancel):
+		p.input.Blur()
+		p.pending = nil
+		return true, nil
+	case key.Matches(keypress, p.keymap.Confirm):
+		resume, err := p.parse()
+		if err != nil {
+			return true, nil
+		}
+
+		action := p.pending
+		p.input.Blur()
+		p.pending = nil
+		return true, action(resume)
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return true, cmd
+}
+
+func (p *PausePrompt) View() string {
+	var b strings.Builder
+	b.WriteString(p.input.View())
+	b.WriteString("\n")
+
+	resume, err := p.parse()
+	switch {
+	case err != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(theme.Negative).Render(err.Error()))
+	case resume != nil:
+		preview := "resumes on " + resume.Format("Monday, January 2, 2006")
+		b.WriteString(lipgloss.NewStyle().Foreground(theme.Muted).Render(preview))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// BankTransaction is a transaction as reported by the bank, read from a statement export
+type BankTransaction struct {
+	Date        time.Time
+	Description string
+	Amount      float32
+}
+
+type ReconcileOptions struct {
+	// relative difference allowed between the bank and forecasted amounts, e.g. 0.1 for 10%
+	AmountTolerance float64
+
+	// number of days the bank date may differ from the forecasted date
+	DateWindow int
+}
+
+func defaultReconcileOptions() ReconcileOptions {
+	return ReconcileOptions{
+		AmountTolerance: 0.1,
+		DateWindow:      5,
+	}
+}
+
+// Match pairs a bank transaction with the forecasted transaction it most likely corresponds to. A
+// match without a transaction is a bank transaction that wasn't forecasted at all.
+type Match struct {
+	bank        BankTransaction
+	transaction *Transaction
+	score       float64
+	accepted    bool
+
+	// set when the bank transaction is already in the history from an earlier import
+	imported bool
+}
+
+func (m *Match) date() time.Time {
+	if m.transaction != nil {
+		return m.transaction.date
+	}
+
+	return m.bank.Date
+}
+
+// reconcile matches each bank transaction to at most one forecasted transaction. Every candidate pair
+// within the amount tolerance and date window is scored on how close the amounts, dates and
+// descriptions are, and the best scoring pairs are taken first.
+func reconcile(bank []BankTransaction, transactions []Transaction, options ReconcileOptions) []Match {
+	type candidate struct {
+		b     int
+		t     int
+		score float64
+	}
+
+	candidates := []candidate{}
+	for b := range bank {
+		for t := range transactions {
+			if score, ok := matchScore(&bank[b], &transactions[t], options); ok {
+				candidates = append(candidates, candidate{b, t, score})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i int, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	matches := make([]Match, len(bank))
+	for b := range bank {
+		matches[b] = Match{bank: bank[b], accepted: true}
+	}
+
+	used := map[int]bool{}
+	for _, c := range candidates {
+		if matches[c.b].transaction != nil || used[c.t] {
+			continue
+		}
+
+		// any number of purchases can be made against a budget envelope
+		if !transactions[c.t].envelope {
+			used[c.t] = true
+		}
+		matches[c.b].transaction = &transactions[c.t]
+		matches[c.b].score = c.score
+	}
+
+	sort.SliceStable(matches, func(i int, j int) bool {
+		return matches[i].bank.Date.Before(matches[j].bank.Date)
+	})
+
+	return matches
+}
+
+func matchScore(b *BankTransaction, t *Transaction, options ReconcileOptions) (float64, bool) {
+	if (b.Amount < 0) != (t.event.Amount < 0) {
+		return 0, false
+	}
+
+	if t.envelope {
+		return envelopeMatchScore(b, t)
+	}
+
+	expected := math.Abs(float64(t.event.Amount))
+	difference := math.Abs(float64(b.Amount - t.event.Amount))
+	allowed := expected * options.AmountTolerance
+	if difference > allowed {
+		return 0, false
+	}
+
+	days := math.Abs(b.Date.Sub(t.date).Hours() / 24)
+	if days > float64(options.DateWindow) {
+		return 0, false
+	}
+
+	amount_score := 1.0
+	if allowed > 0 {
+		amount_score = 1 - difference/allowed
+	}
+	date_score := 1 - days/float64(options.DateWindow+1)
+	description_score := descriptionSimilarity(b.Description, t.event.Description)
+
+	return 0.4*amount_score + 0.3*date_score + 0.3*description_score, true
+}
+
+// envelopeMatchScore scores a bank transaction against the remaining allowance of a budget envelope.
+// Purchases are usually much smaller than the allowance so any amount up to it is accepted, but the
+// description must share a word with the envelope's category.
+func envelopeMatchScore(b *BankTransaction, t *Transaction) (float64, bool) {
+	if math.Abs(float64(b.Amount)) > math.Abs(float64(t.event.Amount)) {
+		return 0, false
+	}
+
+	if b.Date.Before(startOfMonth(t.date)) || !b.Date.Before(startOfMonth(t.date).AddDate(0, 1, 0)) {
+		return 0, false
+	}
+
+	description_score := descriptionSimilarity(b.Description, t.event.Category)
+	if description_score == 0 {
+		return 0, false
+	}
+
+	// rank below any event that matches equally well on description
+	return 0.3 * description_score, true
+}
+
+func descriptionTokens(description string) map[string]bool {
+	tokens := map[string]bool{}
+	fields := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, field := range fields {
+		tokens[field] = true
+	}
+
+	return tokens
+}
+
+// descriptionSimilarity returns the Dice coefficient of the words in both descriptions, a number
+// between 0 (nothing in common) and 1 (the same words)
+func descriptionSimilarity(a string, b string) float64 {
+	a_tokens := descriptionTokens(a)
+	b_tokens := descriptionTokens(b)
+	if len(a_tokens) == 0 || len(b_tokens) == 0 {
+		return 0
+	}
+
+	common := 0
+	for token := range a_tokens {
+		if b_tokens[token] {
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(a_tokens)+len(b_tokens))
+}
+
+// addsToHistory returns whether applying the match adds the bank transaction to the history rather
+// than completing a forecasted transaction
+func (m *Match) addsToHistory() bool {
+	return m.transaction == nil || m.transaction.envelope
+}
+
+// markImported stops bank transactions that are already in the history from being accepted by
+// default, as happens when a statement overlapping an earlier one is imported. That holds for those
+// matched to the forecast too, which would otherwise complete its next occurrence. Each history entry
+// accounts for a single bank transaction so that identical purchases made on the same day still
+// count.
+func (a *Account) markImported(matches []Match) {
+	type entry struct {
+		date        string
+		description string
+		amount      float32
+	}
+
+	keyOf := func(date time.Time, description string, amount float32) entry {
+		return entry{date.Format("2006-01-02"), strings.ToLower(strings.TrimSpace(description)), amount}
+	}
+
+	history := map[entry]int{}
+	for _, h := range a.History {
+		history[keyOf(h.Date, h.Description, h.Amount)]++
+	}
+
+	for i := range matches {
+		match := &matches[i]
+		key := keyOf(match.bank.Date, match.bank.Description, match.bank.Amount)
+		if history[key] > 0 {
+			history[key]--
+			match.imported = true
+			match.accepted = false
+		}
+	}
+}
+
+// applyMatches marks every accepted match done. Matched forecast transactions are completed exactly
+// as if marked done by hand and bank transactions that weren't forecasted are added to the history.
+func (a *Account) applyMatches(matches []Match) {
+	// later occurrences of a repeating event can only be completed after the earlier ones
+	ordered := make([]Match, len(matches))
+	copy(ordered, matches)
+	sort.SliceStable(ordered, func(i int, j int) bool {
+		return ordered[i].date().Before(ordered[j].date())
+	})
+
+	for _, match := range ordered {
+		if !match.accepted {
+			continue
+		}
+
+		if match.addsToHistory() {
+			// spending matched to a budget envelope is recorded against its category
+			var category string
+			if match.transaction != nil {
+				category = match.transaction.event.Category
+			}
+
+			a.Balance += match.bank.Amount
+			a.History = append(a.History, HistoryEntry{
+				Date:        match.bank.Date,
+				Description: match.bank.Description,
+				Amount:      match.bank.Amount,
+				Category:    category,
+			})
+			continue
+		}
+
+		// completing a transaction may move events around in memory so find it again each time
+		tx := a.findTransaction(match.transaction.hash, match.transaction.date)
+		if tx == nil {
+			continue
+		}
+
+		recorded := len(a.History)
+		a.txComplete(tx, true)
+
+		// the history keeps what the bank reported so that importing the statement again recognises
+		// the transaction, and the balance follows the amount actually paid
+		if len(a.History) > recorded {
+			entry := &a.History[len(a.History)-1]
+			a.Balance += match.bank.Amount - entry.Amount
+			entry.Date = match.bank.Date
+			entry.Description = match.bank.Description
+			entry.Amount = match.bank.Amount
+		}
+	}
+}
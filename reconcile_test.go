@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReimportOverlappingStatement(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	account := &Account{
+		Balance: 100,
+		Events: []Event{
+			{ID: "pay", Date: today, Description: "Pay", Amount: 2450, Frequency: Biweekly},
+		},
+	}
+
+	// the bank reports the pay a day late and under its own description
+	bank := []BankTransaction{{today.AddDate(0, 0, 1), "ACME CORP PAYROLL", 2450}}
+	options := defaultReconcileOptions()
+
+	for i := 1; i <= 2; i++ {
+		matches := reconcile(bank, account.predict(today.AddDate(0, 0, 7)), options)
+		account.markImported(matches)
+		account.applyMatches(matches)
+
+		if account.Balance != 2550 {
+			t.Errorf("import %d: balance %.2f, want 2550.00", i, account.Balance)
+		}
+		if len(account.History) != 1 {
+			t.Fatalf("import %d: %d history entries, want 1: %+v", i, len(account.History),
+				account.History)
+		}
+	}
+
+	entry := account.History[0]
+	if !entry.Date.Equal(bank[0].Date) || entry.Description != bank[0].Description {
+		t.Errorf("history records %s %q, want the bank's %s %q", entry.Date.Format("2006-01-02"),
+			entry.Description, bank[0].Date.Format("2006-01-02"), bank[0].Description)
+	}
+
+	if next := account.Events[0].Date; !next.Equal(today.AddDate(0, 0, 14)) {
+		t.Errorf("next pay on %s, want %s", next.Format("2006-01-02"),
+			today.AddDate(0, 0, 14).Format("2006-01-02"))
+	}
+}
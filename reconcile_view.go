@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type ReconcileViewKeyMap struct {
+	Toggle    key.Binding
+	AcceptAll key.Binding
+	RejectAll key.Binding
+	Help      key.Binding
+	Confirm   key.Binding
+	Cancel    key.Binding
+
+	LineUp     key.Binding
+	LineDown   key.Binding
+	GotoTop    key.Binding
+	GotoBottom key.Binding
+}
+
+func NewReconcileViewKeyMap() ReconcileViewKeyMap {
+	k := ReconcileViewKeyMap{
+		Toggle: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space/x", "toggle accept"),
+		),
+		AcceptAll: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "accept all"),
+		),
+		RejectAll: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reject all"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply accepted"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+	}
+
+	settings.bind("reconcile", &k)
+	return k
+}
+
+func (k ReconcileViewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Toggle, k.Confirm, k.Cancel}
+}
+
+func (k ReconcileViewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.LineUp, k.LineDown, k.GotoTop, k.GotoBottom},
+		{k.Toggle, k.AcceptAll, k.RejectAll},
+		{k.Confirm, k.Cancel},
+	}
+}
+
+type ReconcileView struct {
+	keymap ReconcileViewKeyMap
+	help   help.Model
+
+	table table.Model
+
+	account *Account
+	matches []Match
+	balance *float32
+}
+
+func NewReconcileView(account *Account) ReconcileView {
+	columns := []table.Column{
+		{Title: "", Width: 3},
+		{Title: "Bank date", Width: 12},
+		{Title: "Bank description", Width: 30},
+		{Title: "Amount", Width: 12},
+		{Title: "Forecast date", Width: 14},
+		{Title: "Forecast description", Width: 30},
+		{Title: "Amount", Width: 12},
+	}
+
+	style := table.DefaultStyles()
+	style.Header = style.Header.
+		Bold(false).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true)
+
+	style.Selected = style.Selected.
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground).
+		Bold(false)
+
+	t := table.New(
+		table.WithFocused(true),
+		table.WithColumns(columns),
+		table.WithStyles(style),
+	)
+
+	t.KeyMap.PageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.PageDown = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageDown = key.NewBinding(key.WithDisabled())
+
+	keymap := NewReconcileViewKeyMap()
+	t.KeyMap.LineUp = keymap.LineUp
+	t.KeyMap.LineDown = keymap.LineDown
+	t.KeyMap.GotoTop = keymap.GotoTop
+	t.KeyMap.GotoBottom = keymap.GotoBottom
+
+	return ReconcileView{
+		keymap: keymap,
+		help:   help.New(),
+
+		table:   t,
+		account: account,
+	}
+}
+
+func (r *ReconcileView) setMatches(matches []Match, balance *float32) {
+	r.matches = matches
+	r.balance = balance
+	// the cursor can only be placed once there are rows to place it on
+	r.regenerateRows()
+	r.table.SetCursor(0)
+}
+
+func (r *ReconcileView) regenerateRows() {
+	rows := make([]table.Row, 0, len(r.matches))
+	for _, match := range r.matches {
+		accepted := "[ ]"
+		if match.accepted {
+			accepted = "[x]"
+		}
+
+		row := table.Row{
+			accepted,
+			match.bank.Date.Format("Jan 2, 2006"),
+			match.bank.Description,
+			r.account.currency.FormatMoney(match.bank.Amount),
+			"",
+			"(add to history)",
+			"",
+		}
+
+		if match.transaction != nil {
+			row[4] = match.transaction.date.Format("Jan 2, 2006")
+			row[5] = match.transaction.event.Description
+			row[6] = r.account.currency.FormatMoney(match.transaction.event.Amount)
+		}
+
+		if match.imported {
+			row[5] = "(already in history)"
+		}
+
+		rows = append(rows, row)
+	}
+
+	r.table.SetHeight(len(rows))
+	r.table.SetRows(rows)
+}
+
+// apply marks all accepted matches done in the account and, if the statement reported one, sets the
+// balance to the statement's balance
+func (r *ReconcileView) apply() {
+	r.account.applyMatches(r.matches)
+	if r.balance != nil {
+		r.account.Balance = *r.balance
+	}
+
+	r.matches = nil
+	r.balance = nil
+	r.regenerateRows()
+}
+
+func (r *ReconcileView) View() string {
+	matched := 0
+	accepted := 0
+	for _, match := range r.matches {
+		if match.transaction != nil {
+			matched++
+		}
+		if match.accepted {
+			accepted++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d bank transactions, %d matched to the forecast, %d accepted",
+		len(r.matches), matched, accepted))
+	if r.balance != nil {
+		b.WriteString(fmt.Sprintf(", balance will be set to %s", r.account.currency.FormatMoney(*r.balance)))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(r.table.View())
+	b.WriteString("\n\n")
+	b.WriteString(r.help.View(r.keymap))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (r *ReconcileView) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.help.Width = msg.Width
+	case tea.MouseMsg:
+		handleTableMouse(&r.table, r.View, msg)
+		return nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, r.keymap.Help):
+			r.help.ShowAll = !r.help.ShowAll
+		case key.Matches(msg, r.keymap.Toggle):
+			if len(r.matches) > 0 {
+				match := &r.matches[r.table.Cursor()]
+				match.accepted = !match.accepted
+			}
+		case key.Matches(msg, r.keymap.AcceptAll):
+			for i := range r.matches {
+				r.matches[i].accepted = true
+			}
+		case key.Matches(msg, r.keymap.RejectAll):
+			for i := range r.matches {
+				r.matches[i].accepted = false
+			}
+		}
+
+		r.regenerateRows()
+	}
+
+	var cmd tea.Cmd
+	r.table, cmd = r.table.Update(msg)
+	return cmd
+}
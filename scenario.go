@@ -0,0 +1,96 @@
+package main
+
+// Scenario is a named what-if copy of the account's balance and events which can be changed freely
+// without affecting the real account
+type Scenario struct {
+	Name    string
+	Balance float32
+	Events  []Event
+}
+
+func copyEvents(events []Event) []Event {
+	result := make([]Event, len(events))
+	copy(result, events)
+
+	// tags, distributions and dates would otherwise be shared between the copies
+	for i := range result {
+		if result[i].Tags != nil {
+			result[i].Tags = append([]string(nil), result[i].Tags...)
+		}
+
+		if result[i].Distribution != nil {
+			distribution := *result[i].Distribution
+			result[i].Distribution = &distribution
+		}
+
+		if result[i].Until != nil {
+			until := *result[i].Until
+			result[i].Until = &until
+		}
+
+		if result[i].ResumeOn != nil {
+			resume := *result[i].ResumeOn
+			result[i].ResumeOn = &resume
+		}
+	}
+
+	return result
+}
+
+// addScenario clones the account's current balance and events into a new scenario
+func (a *Account) addScenario(name string) {
+	a.Scenarios = append(a.Scenarios, Scenario{
+		Name:    name,
+		Balance: a.Balance,
+		Events:  copyEvents(a.Events),
+	})
+}
+
+func (a *Account) deleteScenario(i int) {
+	a.Scenarios = append(a.Scenarios[:i], a.Scenarios[i+1:]...)
+}
+
+// sandbox returns an account holding the balance and events of scenario i. Everything done to the
+// sandbox stays in the scenario: saving or reloading it saves or reloads the real account with the
+// scenario's changes synced into it.
+func (a *Account) sandbox(i int) *Account {
+	scenario := &a.Scenarios[i]
+
+	return &Account{
+		currency: a.currency,
+
+		Balance:   scenario.Balance,
+		Events:    copyEvents(scenario.Events),
+		History:   append([]HistoryEntry(nil), a.History...),
+		Envelopes: append([]Envelope(nil), a.Envelopes...),
+		Goals:     append([]Goal(nil), a.Goals...),
+
+		BalanceFloor: a.BalanceFloor,
+
+		parent:   a,
+		scenario: i,
+	}
+}
+
+func (a *Account) isSandbox() bool {
+	return a.parent != nil
+}
+
+func (a *Account) scenarioName() string {
+	if !a.isSandbox() {
+		return ""
+	}
+
+	return a.parent.Scenarios[a.scenario].Name
+}
+
+// sync copies a sandbox's balance and events back into its scenario
+func (a *Account) sync() {
+	if !a.isSandbox() {
+		return
+	}
+
+	scenario := &a.parent.Scenarios[a.scenario]
+	scenario.Balance = a.Balance
+	scenario.Events = copyEvents(a.Events)
+}
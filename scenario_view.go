@@ -0,0 +1,318 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type ScenarioViewKeyMap struct {
+	New     key.Binding
+	Discard key.Binding
+	Help    key.Binding
+	Confirm key.Binding
+	Cancel  key.Binding
+
+	LineUp     key.Binding
+	LineDown   key.Binding
+	GotoTop    key.Binding
+	GotoBottom key.Binding
+}
+
+func NewScenarioViewKeyMap() ScenarioViewKeyMap {
+	k := ScenarioViewKeyMap{
+		New: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "new scenario from account"),
+		),
+		Discard: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "discard scenario"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+	}
+
+	settings.bind("scenario", &k)
+	return k
+}
+
+func (k ScenarioViewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.New, k.Confirm, k.Cancel}
+}
+
+func (k ScenarioViewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.LineUp, k.LineDown, k.GotoTop, k.GotoBottom},
+		{k.New, k.Discard},
+		{k.Confirm, k.Cancel},
+	}
+}
+
+// ScenarioView lists the real account followed by each of its scenarios and opens the selected one in
+// the forecast
+type ScenarioView struct {
+	keymap ScenarioViewKeyMap
+	help   help.Model
+
+	table table.Model
+	name  textinput.Model
+
+	account *Account
+
+	// sandbox of the scenario being worked on, nil when working on the real account
+	active *Account
+}
+
+func NewScenarioView(account *Account) ScenarioView {
+	columns := []table.Column{
+		{Title: "", Width: 1},
+		{Title: "Scenario", Width: 30},
+		{Title: "Balance", Width: 15},
+		{Title: "Events", Width: 8},
+		{Title: "Minimum balance", Width: 17},
+		{Title: "On", Width: 20},
+	}
+
+	style := table.DefaultStyles()
+	style.Header = style.Header.
+		Bold(false).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true)
+
+	style.Selected = style.Selected.
+		Foreground(theme.SelectedForeground).
+		Background(theme.SelectedBackground).
+		Bold(false)
+
+	t := table.New(
+		table.WithFocused(true),
+		table.WithColumns(columns),
+		table.WithStyles(style),
+	)
+
+	t.KeyMap.PageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.PageDown = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageUp = key.NewBinding(key.WithDisabled())
+	t.KeyMap.HalfPageDown = key.NewBinding(key.WithDisabled())
+
+	keymap := NewScenarioViewKeyMap()
+	t.KeyMap.LineUp = keymap.LineUp
+	t.KeyMap.LineDown = keymap.LineDown
+	t.KeyMap.GotoTop = keymap.GotoTop
+	t.KeyMap.GotoBottom = keymap.GotoBottom
+
+	name := textinput.New()
+	name.Prompt = "Scenario name: "
+	name.Placeholder = "New car lease"
+
+	return ScenarioView{
+		keymap: keymap,
+		help:   help.New(),
+
+		table: t,
+		name:  name,
+
+		account: account,
+	}
+}
+
+// current returns the account the forecast should show: the active scenario's sandbox if there is
+// one, otherwise the real account
+func (s *ScenarioView) current() *Account {
+	if s.active != nil {
+		return s.active
+	}
+
+	return s.account
+}
+
+// browsing returns whether the view is showing the list of scenarios rather than naming a new one
+func (s *ScenarioView) browsing() bool {
+	return !s.name.Focused()
+}
+
+func (s *ScenarioView) browse() {
+	if s.active != nil {
+		s.active.sync()
+	}
+
+	s.name.Blur()
+	s.table.Focus()
+	s.regenerateRows()
+}
+
+func (s *ScenarioView) regenerateRows() {
+	// the real account always comes first, followed by its scenarios
+	accounts := []*Account{s.account}
+	for i := range s.account.Scenarios {
+		accounts = append(accounts, s.account.sandbox(i))
+	}
+
+	rows := make([]table.Row, 0, len(accounts))
+	for i, account := range accounts {
+		active := ""
+		if (i == 0 && s.active == nil) || (s.active != nil && i == s.active.scenario+1) {
+			active = "*"
+		}
+
+		name := "(account)"
+		if i > 0 {
+			name = account.scenarioName()
+		}
+
+		summary := account.summarize(account.predict(forecastHorizon()))
+		rows = append(rows, table.Row{
+			active,
+			name,
+			s.account.currency.FormatMoney(account.Balance),
+			fmt.Sprintf("%d", len(account.Events)),
+			s.account.currency.FormatMoney(summary.MinimumBalance),
+			summary.MinimumDate.Format("January 2, 2006"),
+		})
+	}
+
+	s.table.SetHeight(len(rows))
+	s.table.SetRows(rows)
+}
+
+// open makes the selected row the active account
+func (s *ScenarioView) open() {
+	if s.active != nil {
+		s.active.sync()
+	}
+
+	i := s.table.Cursor()
+	if i == 0 {
+		s.active = nil
+	} else {
+		s.active = s.account.sandbox(i - 1)
+	}
+
+	s.regenerateRows()
+}
+
+func (s *ScenarioView) discard(i int) {
+	switch {
+	case s.active == nil:
+	case s.active.scenario == i:
+		s.active = nil
+	case s.active.scenario > i:
+		s.active.scenario--
+	}
+
+	s.account.deleteScenario(i)
+}
+
+func (s *ScenarioView) View() string {
+	var b strings.Builder
+	b.WriteString("Scenarios")
+	b.WriteString("\n\n")
+	b.WriteString(s.table.View())
+	b.WriteString("\n\n")
+
+	if s.name.Focused() {
+		b.WriteString(s.name.View())
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(s.help.View(s.keymap))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (s *ScenarioView) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.help.Width = msg.Width
+	case tea.MouseMsg:
+		if !s.name.Focused() {
+			handleTableMouse(&s.table, s.View, msg)
+		}
+		return nil
+	case tea.KeyMsg:
+		if s.name.Focused() {
+			return s.handleNameInput(msg)
+		}
+
+		switch {
+		case key.Matches(msg, s.keymap.Help):
+			s.help.ShowAll = !s.help.ShowAll
+		case key.Matches(msg, s.keymap.New):
+			s.table.Blur()
+			s.name.Reset()
+			s.name.Focus()
+			return nil
+		case key.Matches(msg, s.keymap.Discard):
+			// the real account can't be discarded
+			if i := s.table.Cursor(); i > 0 {
+				s.discard(i - 1)
+				if i >= len(s.account.Scenarios)+1 {
+					s.table.SetCursor(i - 1)
+				}
+				s.regenerateRows()
+			}
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	s.table, cmd = s.table.Update(msg)
+	return cmd
+}
+
+func (s *ScenarioView) handleNameInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, s.keymap.Cancel):
+		s.browse()
+		return nil
+	case key.Matches(msg, s.keymap.Confirm):
+		name := strings.TrimSpace(s.name.Value())
+		if name != "" {
+			s.account.addScenario(name)
+		}
+
+		s.browse()
+		if name != "" {
+			s.table.SetCursor(len(s.account.Scenarios))
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	s.name, cmd = s.name.Update(msg)
+	return cmd
+}
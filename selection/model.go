@@ -73,6 +73,16 @@ func (m *Model) SetSelected(i int) error {
 	return nil
 }
 
+// SelectAt selects the choice shown on the given line of the view, returning whether there was one
+func (m *Model) SelectAt(line int) bool {
+	if line < 0 || line >= len(m.choices) {
+		return false
+	}
+
+	m.selected = line
+	return true
+}
+
 func (m *Model) previous() {
 	m.selected--
 
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/fsareshwala/forecash/confirm"
+	"github.com/fsareshwala/forecash/selection"
+)
+
+// Settings customize the interactive interface. Keys maps a view to the bindings to override in it,
+// each given by its field name in the view's key map, for example:
+//
+//	{
+//	  "Theme": "light",
+//	  "Colors": {"Negative": "196"},
+//	  "Keys": {"forecast": {"Done": ["x", "D"]}},
+//	  "SkipConfirmation": true
+//	}
+type Settings struct {
+	Theme  string                         `json:",omitempty"`
+	Colors map[string]string              `json:",omitempty"`
+	Keys   map[string]map[string][]string `json:",omitempty"`
+
+	// destructive actions such as deleting or marking done happen without asking first
+	SkipConfirmation bool `json:",omitempty"`
+}
+
+// settings are the settings in use, loaded at startup
+var settings Settings
+
+// keyMaps returns the default key map of every view that can be customized, by the name used for
+// it in the settings file
+func keyMaps() map[string]interface{} {
+	forecast := NewForecastViewKeyMap()
+	event := NewEventViewKeyMap()
+	choices := selection.NewKeyMap()
+	question := confirm.NewKeyMap()
+	reconcile := NewReconcileViewKeyMap()
+	category := NewCategoryViewKeyMap()
+	envelope := NewEnvelopeViewKeyMap()
+	goal := NewGoalViewKeyMap()
+	scenario := NewScenarioViewKeyMap()
+	calendar := NewCalendarViewKeyMap()
+	events := NewEventListViewKeyMap()
+	pause := NewPausePromptKeyMap()
+
+	return map[string]interface{}{
+		"forecast":  &forecast,
+		"event":     &event,
+		"selection": &choices,
+		"confirm":   &question,
+		"reconcile": &reconcile,
+		"category":  &category,
+		"envelope":  &envelope,
+		"goal":      &goal,
+		"scenario":  &scenario,
+		"calendar":  &calendar,
+		"events":    &events,
+		"pause":     &pause,
+	}
+}
+
+// loadSettings reads and validates the settings file. A missing file gives the default settings.
+func loadSettings(path string) (Settings, error) {
+	var s Settings
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(content)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&s); err != nil {
+		return s, err
+	}
+
+	if err := s.validate(); err != nil {
+		return s, err
+	}
+
+	return s, nil
+}
+
+func (s *Settings) validate() error {
+	if _, err := s.theme(); err != nil {
+		return err
+	}
+
+	keymaps := keyMaps()
+	for view := range s.Keys {
+		if _, ok := keymaps[view]; !ok {
+			return fmt.Errorf("unknown view %q in keys", view)
+		}
+	}
+
+	for view, keymap := range keymaps {
+		if err := s.bind(view, keymap); err != nil {
+			return err
+		}
+
+		if err := checkConflicts(view, keymap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// theme returns the chosen theme preset with any colors overridden
+func (s *Settings) theme() (Theme, error) {
+	name := s.Theme
+	if name == "" {
+		name = "dark"
+	}
+
+	t, ok := themes[name]
+	if !ok {
+		return t, fmt.Errorf("unknown theme %q, expected dark or light", name)
+	}
+
+	for color, value := range s.Colors {
+		if err := t.setColor(color, value); err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
+
+// bind overrides the bindings of the given key map, which must be a pointer to a struct of
+// key.Binding fields, with those set for the view
+func (s *Settings) bind(view string, keymap interface{}) error {
+	value := reflect.ValueOf(keymap).Elem()
+
+	for name, keys := range s.Keys[view] {
+		field := value.FieldByName(name)
+		if !field.IsValid() || field.Type() != reflect.TypeOf(key.Binding{}) {
+			return fmt.Errorf("unknown binding %q in %s keys", name, view)
+		}
+
+		if len(keys) == 0 {
+			return fmt.Errorf("%s binding %s has no keys", view, name)
+		}
+
+		binding := field.Addr().Interface().(*key.Binding)
+		binding.SetKeys(keys...)
+		binding.SetHelp(strings.Join(keys, "/"), binding.Help().Desc)
+	}
+
+	return nil
+}
+
+// checkConflicts returns an error if a key triggers more than one binding at once. Bindings tagged
+// with different modes are never active at the same time and so may share keys.
+func checkConflicts(view string, keymap interface{}) error {
+	value := reflect.ValueOf(keymap).Elem()
+
+	type use struct {
+		name string
+		mode string
+	}
+
+	uses := map[string][]use{}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		binding, ok := value.Field(i).Interface().(key.Binding)
+		if !ok || !binding.Enabled() {
+			continue
+		}
+
+		for _, k := range binding.Keys() {
+			uses[k] = append(uses[k], use{name: field.Name, mode: field.Tag.Get("mode")})
+		}
+	}
+
+	keys := make([]string, 0, len(uses))
+	for k := range uses {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for i, a := range uses[k] {
+			for _, b := range uses[k][i+1:] {
+				if a.mode == "" || b.mode == "" || a.mode == b.mode {
+					return fmt.Errorf("%s key %q is bound to both %s and %s", view, k, a.name, b.name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
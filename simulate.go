@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	simulationRuns = 1000
+	simulationSeed = 1
+)
+
+// Distribution describes how much an event's amount may vary from one occurrence to the next, either
+// uniformly within a range or normally around the event's amount. Ranges are given as magnitudes and
+// take the sign of the event's amount, so a bill of -100 may range from 80 to 120.
+type Distribution struct {
+	Min    float32 `json:",omitempty"`
+	Max    float32 `json:",omitempty"`
+	StdDev float32 `json:",omitempty"`
+}
+
+// parseDistribution accepts a range such as "80..120" or a standard deviation such as "±15". An empty
+// string means the amount doesn't vary.
+func parseDistribution(str string) (*Distribution, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return nil, nil
+	}
+
+	if parts := strings.SplitN(str, "..", 2); len(parts) == 2 {
+		minimum, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 32)
+		if err != nil {
+			return nil, fmt.Errorf("range minimum is invalid")
+		}
+
+		maximum, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 32)
+		if err != nil {
+			return nil, fmt.Errorf("range maximum is invalid")
+		}
+
+		minimum = math.Abs(minimum)
+		maximum = math.Abs(maximum)
+		if minimum > maximum {
+			minimum, maximum = maximum, minimum
+		}
+
+		return &Distribution{Min: float32(minimum), Max: float32(maximum)}, nil
+	}
+
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(str, "±"), "+-")
+	stddev, err := strconv.ParseFloat(strings.TrimSpace(trimmed), 32)
+	if err != nil || stddev < 0 {
+		return nil, fmt.Errorf("expected a range like 80..120 or a standard deviation like ±15")
+	}
+
+	return &Distribution{StdDev: float32(stddev)}, nil
+}
+
+func (d *Distribution) toString() string {
+	if d == nil {
+		return ""
+	}
+
+	if d.StdDev > 0 {
+		return fmt.Sprintf("±%.02f", d.StdDev)
+	}
+
+	return fmt.Sprintf("%.02f..%.02f", d.Min, d.Max)
+}
+
+// sample draws an amount for a single occurrence of the event
+func (e *Event) sample(rng *rand.Rand) float32 {
+	d := e.Distribution
+	switch {
+	case d == nil:
+		return e.Amount
+	case d.StdDev > 0:
+		return e.Amount + float32(rng.NormFloat64())*d.StdDev
+	case d.Max > d.Min:
+		amount := d.Min + rng.Float32()*(d.Max-d.Min)
+		if e.Amount < 0 {
+			return -amount
+		}
+		return amount
+	}
+
+	return e.Amount
+}
+
+type SimulationResult struct {
+	// the 10th, 50th and 90th percentile of the running balance after each transaction
+	P10 []float32
+	P50 []float32
+	P90 []float32
+
+	// fraction of runs in which the balance went below zero before the end of the forecast
+	NegativeProbability float64
+}
+
+// simulationInput fingerprints everything a simulation of the transactions depends on, so that it's
+// only run again once something that changes its result has changed
+func (a *Account) simulationInput(transactions []Transaction) uint64 {
+	hasher := fnv.New64a()
+	write := func(values ...interface{}) {
+		for _, value := range values {
+			binary.Write(hasher, binary.LittleEndian, value)
+		}
+	}
+
+	write(a.Balance)
+	for _, t := range transactions {
+		write(t.date.Unix(), t.event.Amount)
+		if d := t.event.Distribution; d != nil {
+			write(d.Min, d.Max, d.StdDev)
+		}
+	}
+
+	return hasher.Sum64()
+}
+
+// simulate runs the forecast many times, drawing the amount of every event with a distribution anew
+// each time, and reports how widely the running balance varies. The random number generator is
+// seeded so the same forecast always gives the same result.
+func (a *Account) simulate(transactions []Transaction, runs int, seed int64) SimulationResult {
+	rng := rand.New(rand.NewSource(seed))
+
+	// balances[i] holds the balance after transaction i in each run
+	balances := make([][]float32, len(transactions))
+	for i := range balances {
+		balances[i] = make([]float32, runs)
+	}
+
+	negative := 0
+	for run := 0; run < runs; run++ {
+		balance := a.Balance
+		went_negative := balance < 0
+
+		for i := range transactions {
+			balance += transactions[i].event.sample(rng)
+			balances[i][run] = balance
+
+			if balance < 0 {
+				went_negative = true
+			}
+		}
+
+		if went_negative {
+			negative++
+		}
+	}
+
+	result := SimulationResult{
+		P10: make([]float32, len(transactions)),
+		P50: make([]float32, len(transactions)),
+		P90: make([]float32, len(transactions)),
+	}
+
+	for i := range balances {
+		sort.Slice(balances[i], func(x int, y int) bool {
+			return balances[i][x] < balances[i][y]
+		})
+
+		result.P10[i] = percentile(balances[i], 0.1)
+		result.P50[i] = percentile(balances[i], 0.5)
+		result.P90[i] = percentile(balances[i], 0.9)
+	}
+
+	if runs > 0 {
+		result.NegativeProbability = float64(negative) / float64(runs)
+	}
+
+	return result
+}
+
+// percentile returns the value at percentile p of the sorted values using the nearest rank method
+func percentile(sorted []float32, p float64) float32 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	i := int(p*float64(len(sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+
+	return sorted[i]
+}
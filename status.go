@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatusMsg is sent by the views to report the outcome of an action in the status line
+type StatusMsg struct {
+	text  string
+	error bool
+}
+
+func status(format string, args ...interface{}) tea.Cmd {
+	return func() tea.Msg {
+		return StatusMsg{text: fmt.Sprintf(format, args...)}
+	}
+}
+
+// statusError reports that the action failed with the given error
+func statusError(action string, err error) tea.Cmd {
+	return func() tea.Msg {
+		return StatusMsg{text: fmt.Sprintf("%s failed: %v", action, err), error: true}
+	}
+}
+
+// saveAccount saves the account and reports how it went
+func saveAccount(account *Account) tea.Cmd {
+	if err := account.save(); err != nil {
+		return statusError("Save", err)
+	}
+
+	return status("Saved %d events", len(account.Events))
+}
+
+// reloadAccount reloads the account and reports how it went
+func reloadAccount(account *Account) tea.Cmd {
+	if err := account.reload(); err != nil {
+		return statusError("Reload", err)
+	}
+
+	return status("Reloaded %d events", len(account.Events))
+}
+
+func (s StatusMsg) View() string {
+	if s.error {
+		return lipgloss.NewStyle().Foreground(theme.Negative).Render(s.text)
+	}
+
+	return lipgloss.NewStyle().Foreground(theme.Muted).Render(s.text)
+}
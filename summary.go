@@ -0,0 +1,39 @@
+package main
+
+import (
+	"time"
+)
+
+type BalanceSummary struct {
+	MinimumBalance float32
+	MinimumDate    time.Time
+	SafeToSpend    float32
+}
+
+// summarize walks the running balance of the given transactions, which must already be sorted by
+// date, and reports the lowest point the balance reaches along with how much could be spent today
+// without the balance ever dropping below the account's minimum balance.
+func (a *Account) summarize(transactions []Transaction) BalanceSummary {
+	now := time.Now()
+	summary := BalanceSummary{
+		MinimumBalance: a.Balance,
+		MinimumDate:    time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local),
+	}
+
+	balance := a.Balance
+	for _, transaction := range transactions {
+		balance += transaction.event.Amount
+		if balance < summary.MinimumBalance {
+			summary.MinimumBalance = balance
+			summary.MinimumDate = transaction.date
+		}
+	}
+
+	// spending money today lowers every future balance by the same amount, so the most that can be
+	// spent is however far the lowest point is above the minimum balance
+	if summary.MinimumBalance > a.BalanceFloor {
+		summary.SafeToSpend = summary.MinimumBalance - a.BalanceFloor
+	}
+
+	return summary
+}
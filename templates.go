@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+)
+
+// EventTemplate is a saved starting point for events that are added over and over. Templates are
+// also built from existing events and history to autocomplete descriptions.
+type EventTemplate struct {
+	Description  string
+	Amount       float32
+	Frequency    Frequency
+	Category     string        `json:",omitempty"`
+	Tags         []string      `json:",omitempty"`
+	Distribution *Distribution `json:",omitempty"`
+}
+
+func templateOf(event *Event) EventTemplate {
+	copied := copyEvents([]Event{*event})[0]
+
+	return EventTemplate{
+		Description:  copied.Description,
+		Amount:       copied.Amount,
+		Frequency:    copied.Frequency,
+		Category:     copied.Category,
+		Tags:         copied.Tags,
+		Distribution: copied.Distribution,
+	}
+}
+
+// root returns the real account behind a scenario's sandbox. Templates are kept there so they can
+// be used and saved from any scenario.
+func (a *Account) root() *Account {
+	for a.parent != nil {
+		a = a.parent
+	}
+
+	return a
+}
+
+func (a *Account) templates() []EventTemplate {
+	return a.root().Templates
+}
+
+// saveTemplate adds the template, replacing any other with the same description
+func (a *Account) saveTemplate(template EventTemplate) {
+	root := a.root()
+	for i := range root.Templates {
+		if strings.EqualFold(root.Templates[i].Description, template.Description) {
+			root.Templates[i] = template
+			return
+		}
+	}
+
+	root.Templates = append(root.Templates, template)
+}
+
+func (a *Account) deleteTemplate(i int) {
+	root := a.root()
+	root.Templates = append(root.Templates[:i], root.Templates[i+1:]...)
+}
+
+// descriptionSuggestions returns a template for every description used so far, taking the amount
+// and such from saved templates first, then the most recently added events and then the most
+// recently completed transactions
+func (a *Account) descriptionSuggestions() []EventTemplate {
+	var result []EventTemplate
+	seen := map[string]bool{}
+
+	add := func(template EventTemplate) {
+		key := strings.ToLower(strings.TrimSpace(template.Description))
+		if key != "" && !seen[key] {
+			seen[key] = true
+			result = append(result, template)
+		}
+	}
+
+	for _, template := range a.templates() {
+		add(template)
+	}
+
+	for i := len(a.Events) - 1; i >= 0; i-- {
+		add(templateOf(&a.Events[i]))
+	}
+
+	for i := len(a.History) - 1; i >= 0; i-- {
+		entry := &a.History[i]
+		add(EventTemplate{
+			Description: entry.Description,
+			Amount:      entry.Amount,
+			Frequency:   Once,
+			Category:    entry.Category,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors used throughout the interface
+type Theme struct {
+	SelectedForeground lipgloss.Color
+	SelectedBackground lipgloss.Color
+
+	// labels and chart lines
+	Accent lipgloss.Color
+
+	// negative balances and the lowest point of the chart
+	Negative lipgloss.Color
+
+	// dimmed text such as days outside the month being shown
+	Muted lipgloss.Color
+
+	// the minimum balance threshold on the chart
+	Warning lipgloss.Color
+}
+
+var themes = map[string]Theme{
+	"dark": {
+		SelectedForeground: lipgloss.Color("229"),
+		SelectedBackground: lipgloss.Color("27"),
+		Accent:             lipgloss.Color("27"),
+		Negative:           lipgloss.Color("1"),
+		Muted:              lipgloss.Color("240"),
+		Warning:            lipgloss.Color("214"),
+	},
+	"light": {
+		SelectedForeground: lipgloss.Color("231"),
+		SelectedBackground: lipgloss.Color("25"),
+		Accent:             lipgloss.Color("25"),
+		Negative:           lipgloss.Color("124"),
+		Muted:              lipgloss.Color("245"),
+		Warning:            lipgloss.Color("130"),
+	},
+}
+
+// theme is the theme in use, chosen by the settings file
+var theme = themes["dark"]
+
+// setColor overrides a single color of the theme by its field name
+func (t *Theme) setColor(name string, color string) error {
+	value := lipgloss.Color(color)
+
+	switch name {
+	case "SelectedForeground":
+		t.SelectedForeground = value
+	case "SelectedBackground":
+		t.SelectedBackground = value
+	case "Accent":
+		t.Accent = value
+	case "Negative":
+		t.Negative = value
+	case "Muted":
+		t.Muted = value
+	case "Warning":
+		t.Warning = value
+	default:
+		return fmt.Errorf("unknown color %q", name)
+	}
+
+	return nil
+}
+
+// negative renders text in the theme's color for negative amounts
+func (t *Theme) negative(str string) string {
+	return lipgloss.NewStyle().Foreground(t.Negative).Render(str)
+}
+
+// faint renders a table cell dimmed. The table counts escape codes towards the width of a cell, so
+// the terminal's faint attribute is used rather than a color as its codes are the shortest, and a
+// cell is left as it is when there is no room for them at all.
+func faint(str string, width int) string {
+	rendered := lipgloss.NewStyle().Faint(true).Render(str)
+	if lipgloss.Width(str)+len(rendered)-len(str) > width {
+		return str
+	}
+
+	return rendered
+}
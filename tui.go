@@ -14,13 +14,40 @@ type State int
 const (
 	stateForecastView State = iota
 	stateEventView
+	stateReconcileView
+	stateCategoryView
+	stateEnvelopeView
+	stateGoalView
+	stateScenarioView
+	stateCalendarView
+	stateEventListView
 )
 
 type Tui struct {
-	forecastView ForecastView
-	eventView    EventView
+	forecastView  ForecastView
+	eventView     EventView
+	reconcileView ReconcileView
+	categoryView  CategoryView
+	envelopeView  EnvelopeView
+	goalView      GoalView
+	scenarioView  ScenarioView
+	calendarView  CalendarView
+	eventListView EventListView
+
+	state State
+
+	// outcome of the last action, cleared on the next keypress
+	status StatusMsg
+
+	// view to go back to once the event being added or edited is confirmed or cancelled
+	eventReturn State
+
+	// occurrence a series is being changed from, the event being added replaces the series from there
+	splitAt *Transaction
+
+	// height of the terminal, views taller than it are drawn without their first lines
+	height int
 
-	state   State
 	account *Account
 }
 
@@ -30,9 +57,32 @@ func (t Tui) Init() tea.Cmd {
 
 func (t Tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	f := NewForecastViewKeyMap()
+	r := NewReconcileViewKeyMap()
+	c := NewCategoryViewKeyMap()
+	v := NewEnvelopeViewKeyMap()
+	g := NewGoalViewKeyMap()
+	z := NewScenarioViewKeyMap()
+	k := NewCalendarViewKeyMap()
+	e := NewEventListViewKeyMap()
+
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		msg = t.onView(mouse)
+	}
 
 	switch msg := msg.(type) {
+	case StatusMsg:
+		t.status = msg
+		return t, nil
+	case tea.WindowSizeMsg:
+		t.height = msg.Height
+
+		// the event list is sized to the window even while it isn't shown
+		if t.state != stateEventListView {
+			t.eventListView.Update(msg)
+		}
 	case tea.KeyMsg:
+		t.status = StatusMsg{}
+
 		switch {
 		// Although these keypresses are enabled only for specific views, we check here because there is
 		// no way for a subview to inform the parent view to switch to another subview (e.g. going from
@@ -43,30 +93,164 @@ func (t Tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// creating a new copy of Tui each time Update or View is called.
 		//
 		// ForecastView keypresses
-		case t.state == stateForecastView && key.Matches(msg, f.AddEvent):
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.AddEvent):
 			t.eventView.unsetEvent()
-			t.state = stateEventView
+			t.editEvent(stateForecastView)
+			return t, nil
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.EditEvent):
+			tx := t.forecastView.getSelectedTransaction()
+			if tx == nil {
+				return t, nil
+			}
+
+			// an envelope's allowance isn't an event, it's changed along with the envelope
+			if tx.envelope {
+				t.envelopeView.setAccount(t.forecastView.account)
+				t.envelopeView.browse()
+				t.envelopeView.setCursorToCategory(tx.event.Category)
+				t.state = stateEnvelopeView
+				return t, nil
+			}
+
+			t.eventView.setEvent(tx.event)
+			t.editEvent(stateForecastView)
+			return t, nil
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.EditOnward):
+			tx := t.forecastView.getSelectedTransaction()
+			if tx == nil || tx.envelope {
+				return t, nil
+			}
+
+			if tx.repeats() && !tx.isFirstOccurrence() {
+				split := *tx
+				t.splitAt = &split
+				t.eventView.copyEvent(tx.event, tx.date)
+			} else {
+				// changing the series from its first occurrence on is the same as editing it
+				t.eventView.setEvent(tx.event)
+			}
+
+			t.editEvent(stateForecastView)
+			return t, nil
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.CategorySummary):
+			t.categoryView.setAccount(t.forecastView.account)
+			t.categoryView.regenerateRows()
+			t.state = stateCategoryView
+			return t, nil
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.Envelopes):
+			t.envelopeView.setAccount(t.forecastView.account)
+			t.envelopeView.browse()
+			t.state = stateEnvelopeView
+			return t, nil
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.Goals):
+			t.goalView.setAccount(t.forecastView.account)
+			t.goalView.browse()
+			t.state = stateGoalView
+			return t, nil
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.Scenarios):
+			t.scenarioView.browse()
+			t.state = stateScenarioView
 			return t, nil
-		case t.state == stateForecastView && key.Matches(msg, f.EditEvent):
-			t.eventView.setEvent(t.forecastView.getSelectedTransaction().event)
-			t.state = stateEventView
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.Calendar):
+			t.calendarView.setAccount(t.forecastView.account)
+			t.calendarView.browse()
+			t.state = stateCalendarView
 			return t, nil
-		case t.state == stateForecastView && key.Matches(msg, f.Quit):
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.Events):
+			t.eventListView.setAccount(t.forecastView.account)
+			t.eventListView.regenerateRows()
+			t.state = stateEventListView
+			return t, nil
+		case t.state == stateForecastView && t.forecastView.browsing() && key.Matches(msg, f.Quit):
 			return t, tea.Quit
 
 		// EventView keypresses
-		case t.state == stateEventView && key.Matches(msg, f.FocusTable):
+		case t.state == stateEventView && !t.eventView.choosingTemplate() && key.Matches(msg, f.FocusTable):
 			t.eventView.unsetEvent()
-			t.state = stateForecastView
+			t.splitAt = nil
+			t.state = t.eventReturn
 			return t, nil
-		case t.state == stateEventView && key.Matches(msg, f.Confirm):
+		case t.state == stateEventView && !t.eventView.choosingTemplate() && key.Matches(msg, f.Confirm):
+			if err := t.eventView.validate(); err != nil {
+				return t, nil
+			}
+
+			// getEvent changes the event being edited in place, so the account is remembered first
+			t.forecastView.remember()
+
 			// we must call getEvent in both add or edit mode: it pulls data from textinputs
 			event := t.eventView.getEvent()
-			if !t.eventView.hasEvent() {
-				t.account.addEvent(event)
+			if t.splitAt != nil {
+				t.forecastView.account.splitSeries(t.splitAt, event)
+				t.splitAt = nil
+			} else if !t.eventView.hasEvent() {
+				t.forecastView.account.addEvent(event)
 			}
 
 			t.eventView.unsetEvent()
+			t.state = t.eventReturn
+			t.forecastView.regenerateRows()
+			t.eventListView.regenerateRows()
+			return t, nil
+
+		// ReconcileView keypresses
+		case t.state == stateReconcileView && key.Matches(msg, r.Cancel):
+			t.state = stateForecastView
+			return t, nil
+		case t.state == stateReconcileView && key.Matches(msg, r.Confirm):
+			t.forecastView.remember()
+			t.reconcileView.apply()
+			t.state = stateForecastView
+			t.forecastView.regenerateRows()
+			return t, nil
+
+		// CategoryView keypresses
+		case t.state == stateCategoryView && key.Matches(msg, c.Cancel):
+			t.state = stateForecastView
+			return t, nil
+
+		// EnvelopeView keypresses
+		case t.state == stateEnvelopeView && t.envelopeView.browsing() && key.Matches(msg, v.Cancel):
+			t.state = stateForecastView
+			t.forecastView.regenerateRows()
+			return t, nil
+
+		// GoalView keypresses
+		case t.state == stateGoalView && t.goalView.browsing() && key.Matches(msg, g.Cancel):
+			t.state = stateForecastView
+			t.forecastView.regenerateRows()
+			return t, nil
+
+		// ScenarioView keypresses
+		case t.state == stateScenarioView && t.scenarioView.browsing() && key.Matches(msg, z.Cancel):
+			// the open scenario may have been discarded
+			t.forecastView.setAccount(t.scenarioView.current())
+			t.state = stateForecastView
+			t.forecastView.regenerateRows()
+			return t, nil
+		case t.state == stateScenarioView && t.scenarioView.browsing() && key.Matches(msg, z.Confirm):
+			t.scenarioView.open()
+			t.forecastView.setAccount(t.scenarioView.current())
+			t.state = stateForecastView
+			return t, nil
+
+		// EventListView keypresses
+		case t.state == stateEventListView && t.eventListView.browsing() && key.Matches(msg, e.Edit):
+			event := t.eventListView.getSelectedEvent()
+			if event == nil {
+				return t, nil
+			}
+
+			t.eventView.setEvent(event)
+			t.editEvent(stateEventListView)
+			return t, nil
+		case t.state == stateEventListView && t.eventListView.browsing() && key.Matches(msg, e.Cancel):
+			t.state = stateForecastView
+			t.forecastView.regenerateRows()
+			return t, nil
+
+		// CalendarView keypresses
+		case t.state == stateCalendarView && t.calendarView.browsing() && key.Matches(msg, k.Cancel):
 			t.state = stateForecastView
 			t.forecastView.regenerateRows()
 			return t, nil
@@ -79,11 +263,35 @@ func (t Tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd = t.forecastView.Update(msg)
 	case stateEventView:
 		cmd = t.eventView.Update(msg)
+	case stateReconcileView:
+		cmd = t.reconcileView.Update(msg)
+	case stateCategoryView:
+		cmd = t.categoryView.Update(msg)
+	case stateEnvelopeView:
+		cmd = t.envelopeView.Update(msg)
+	case stateGoalView:
+		cmd = t.goalView.Update(msg)
+	case stateScenarioView:
+		cmd = t.scenarioView.Update(msg)
+	case stateCalendarView:
+		cmd = t.calendarView.Update(msg)
+	case stateEventListView:
+		cmd = t.eventListView.Update(msg)
 	}
 
 	return t, cmd
 }
 
+// onView moves a mouse event from the screen onto the view, which is drawn from the top of the screen
+// unless it's too tall to fit
+func (t Tui) onView(msg tea.MouseMsg) tea.MouseMsg {
+	if lines := strings.Count(t.View(), "\n") + 1; t.height > 0 && lines > t.height {
+		msg.Y += lines - t.height
+	}
+
+	return msg
+}
+
 func (t Tui) View() string {
 	var b strings.Builder
 
@@ -92,15 +300,49 @@ func (t Tui) View() string {
 		b.WriteString(t.forecastView.View())
 	case stateEventView:
 		b.WriteString(t.eventView.View())
+	case stateReconcileView:
+		b.WriteString(t.reconcileView.View())
+	case stateCategoryView:
+		b.WriteString(t.categoryView.View())
+	case stateEnvelopeView:
+		b.WriteString(t.envelopeView.View())
+	case stateGoalView:
+		b.WriteString(t.goalView.View())
+	case stateScenarioView:
+		b.WriteString(t.scenarioView.View())
+	case stateCalendarView:
+		b.WriteString(t.calendarView.View())
+	case stateEventListView:
+		b.WriteString(t.eventListView.View())
+	}
+
+	b.WriteString(t.status.View())
+	if t.state == stateForecastView {
+		if summary := t.forecastView.selectionSummary(); summary != "" {
+			if t.status.text != "" {
+				b.WriteString("  ")
+			}
+			b.WriteString(summary)
+		}
 	}
 
 	return b.String()
 }
 
 func newTui(account *Account) Tui {
+	// every view that changes the account records into the same history so the forecast can undo it
+	undo := &UndoHistory{}
+
 	t := Tui{
-		forecastView: NewForecastView(account),
-		eventView:    NewEventView(),
+		forecastView:  NewForecastView(account, undo),
+		eventView:     NewEventView(),
+		reconcileView: NewReconcileView(account),
+		categoryView:  NewCategoryView(account),
+		envelopeView:  NewEnvelopeView(account, undo),
+		goalView:      NewGoalView(account, undo),
+		scenarioView:  NewScenarioView(account),
+		calendarView:  NewCalendarView(account, undo),
+		eventListView: NewEventListView(account, undo),
 
 		state:   stateForecastView,
 		account: account,
@@ -111,8 +353,22 @@ func newTui(account *Account) Tui {
 	return t
 }
 
+// reconcile starts the interface on the reconciliation screen for the given matches. If balance is
+// given, the account balance is set to it once the matches are applied.
+func (t *Tui) reconcile(matches []Match, balance *float32) {
+	t.reconcileView.setMatches(matches, balance)
+	t.state = stateReconcileView
+}
+
+// editEvent switches to the event view, returning to the given view once done
+func (t *Tui) editEvent(from State) {
+	t.eventView.setAccount(t.forecastView.account)
+	t.eventReturn = from
+	t.state = stateEventView
+}
+
 func (t *Tui) run() {
-	if _, err := tea.NewProgram(*t).Run(); err != nil {
+	if _, err := tea.NewProgram(*t, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,64 @@
+package main
+
+// Snapshot is a copy of everything in the account that the forecast's actions can change, so they
+// can be undone
+type Snapshot struct {
+	balance   float32
+	events    []Event
+	history   []HistoryEntry
+	envelopes []Envelope
+	goals     []Goal
+}
+
+// only this many actions can be undone
+const undoLimit = 100
+
+// UndoHistory holds the account states to go back to, most recent last. Views share it by pointer so
+// actions run after a confirmation, which hold on to an older copy of the view, still record into it.
+type UndoHistory struct {
+	snapshots []Snapshot
+}
+
+// remember saves the state of the account so the next change can be undone
+func (u *UndoHistory) remember(a *Account) {
+	u.snapshots = append(u.snapshots, a.snapshot())
+	if len(u.snapshots) > undoLimit {
+		u.snapshots = u.snapshots[1:]
+	}
+}
+
+// undo puts the account back the way it was before the last change, returning false if there is
+// nothing to undo
+func (u *UndoHistory) undo(a *Account) bool {
+	if len(u.snapshots) == 0 {
+		return false
+	}
+
+	last := len(u.snapshots) - 1
+	a.restore(u.snapshots[last])
+	u.snapshots = u.snapshots[:last]
+	return true
+}
+
+// forget drops every saved state, keeping the history shared with the views that record into it
+func (u *UndoHistory) forget() {
+	u.snapshots = nil
+}
+
+func (a *Account) snapshot() Snapshot {
+	return Snapshot{
+		balance:   a.Balance,
+		events:    copyEvents(a.Events),
+		history:   append([]HistoryEntry(nil), a.History...),
+		envelopes: append([]Envelope(nil), a.Envelopes...),
+		goals:     append([]Goal(nil), a.Goals...),
+	}
+}
+
+func (a *Account) restore(s Snapshot) {
+	a.Balance = s.balance
+	a.Events = s.events
+	a.History = s.history
+	a.Envelopes = s.envelopes
+	a.Goals = s.goals
+}